@@ -0,0 +1,156 @@
+package edau
+
+import "io"
+import "math"
+import "sync"
+
+const agcBlockFrames = 64 // ~1.5ms at 44.1kHz
+
+// Blocks with a level below this value are considered silent and
+// don't modify the gain (otherwise background noise would be boosted
+// up to the ceiling during pauses). Roughly -80dB.
+const agcSilenceLevel = 0.0001
+
+// An AGC (automatic gain control) wraps an audio stream and slowly
+// adjusts its volume so the level approaches a given target. Unlike a
+// compressor, which only reduces the gain when the signal goes above a
+// threshold, the AGC can both attenuate loud passages and amplify quiet
+// ones, up to a configurable gain ceiling.
+//
+// The level is measured in blocks of 64 samples with [MeasureRMS]. The
+// attack time controls how fast the gain goes down when the signal is
+// too loud, and the release time how fast it goes up when the signal is
+// too quiet. Both are typically long, in the order of seconds, as the
+// goal is to even out the level, not to shape transients. Silent blocks
+// leave the gain unchanged.
+//
+// Notice that the output is clamped to [-1, 1] but not limited in any
+// smarter way, so aggressive targets or ceilings may cause clipping.
+type AGC struct {
+	mutex sync.Mutex
+	source io.Reader
+	target float64
+	maxGain float64
+	attackCoef float64
+	releaseCoef float64
+	gain float64
+}
+
+// Creates a new [AGC]. The target level is a normalized RMS level in
+// (0, 1], the max gain must be >= 1, and the attack and release times
+// are given in seconds and can't be negative. This method will panic
+// if any of the values are invalid.
+// If the sample rate is 0, [DefaultSampleRate] is used.
+func NewAGC(source io.Reader, targetLevel float64, maxGain float64, attack, release float64, sampleRate int) *AGC {
+	sampleRate = resolveSampleRate(sampleRate, "NewAGC")
+	assertAGCTargetValidity(targetLevel)
+	assertAGCMaxGainValidity(maxGain)
+	if attack < 0 || release < 0 { panic("NewAGC attack and release times can't be negative") }
+	return &AGC {
+		source: source,
+		target: targetLevel,
+		maxGain: maxGain,
+		attackCoef: smoothingCoef(attack, sampleRate),
+		releaseCoef: smoothingCoef(release, sampleRate),
+		gain: 1.0,
+	}
+}
+
+// Returns the underlying source.
+func (self *AGC) Source() io.Reader {
+	return self.source
+}
+
+// Returns the currently configured target level.
+func (self *AGC) Target() float64 {
+	self.mutex.Lock()
+	target := self.target
+	self.mutex.Unlock()
+	return target
+}
+
+// Sets the target level, which must be in (0, 1]. This method will
+// panic if the value is invalid.
+func (self *AGC) SetTarget(targetLevel float64) {
+	assertAGCTargetValidity(targetLevel)
+	self.mutex.Lock()
+	self.target = targetLevel
+	self.mutex.Unlock()
+}
+
+// Returns the currently configured gain ceiling.
+func (self *AGC) MaxGain() float64 {
+	self.mutex.Lock()
+	maxGain := self.maxGain
+	self.mutex.Unlock()
+	return maxGain
+}
+
+// Sets the gain ceiling, which must be >= 1. If the current gain is
+// above the new ceiling, it's clamped immediately. This method will
+// panic if the value is invalid.
+func (self *AGC) SetMaxGain(maxGain float64) {
+	assertAGCMaxGainValidity(maxGain)
+	self.mutex.Lock()
+	self.maxGain = maxGain
+	if self.gain > maxGain { self.gain = maxGain }
+	self.mutex.Unlock()
+}
+
+// Returns the gain currently being applied.
+func (self *AGC) Gain() float64 {
+	self.mutex.Lock()
+	gain := self.gain
+	self.mutex.Unlock()
+	return gain
+}
+
+// Implements [io.Reader]. The returned read length will always be
+// multiple of 4, aligning to Ebitengine's sample size.
+func (self *AGC) Read(buffer []byte) (int, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	n, err := readFrames(self.source, buffer)
+	if n == 0 { return n, err }
+
+	for start := 0; start < n; start += agcBlockFrames*4 {
+		end := start + agcBlockFrames*4
+		if end > n { end = n }
+		level := math.Max(MeasureRMS(buffer[start : end]))
+		target := self.gain
+		if level >= agcSilenceLevel {
+			target = math.Min(self.target/level, self.maxGain)
+		}
+		for i := start; i < end; i += 4 {
+			if target < self.gain {
+				self.gain += (target - self.gain)*self.attackCoef
+			} else {
+				self.gain += (target - self.gain)*self.releaseCoef
+			}
+			left, right := GetSampleAsF64(buffer[i : ])
+			StoreNormF64SampleAsL16(buffer[i : ], left*self.gain, right*self.gain)
+		}
+	}
+	return n, err
+}
+
+// Implements [io.Seeker]. The gain is reset to 1 after seeking.
+//
+// If the underlying source doesn't implement [io.Seeker], [ErrNotSeekable]
+// is returned.
+func (self *AGC) Seek(offset int64, whence int) (int64, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	position, err := seekSource(self.source, offset, whence)
+	self.gain = 1.0
+	return position, err
+}
+
+func assertAGCTargetValidity(target float64) {
+	if target <= 0 || target > 1.0 { panic("AGC target level must be in (0, 1]") }
+}
+
+func assertAGCMaxGainValidity(maxGain float64) {
+	if maxGain < 1.0 { panic("AGC max gain must be >= 1") }
+}
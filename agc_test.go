@@ -0,0 +1,28 @@
+package edau
+
+import "io"
+import "math"
+import "bytes"
+import "time"
+import "testing"
+
+func TestAGC(t *testing.T) {
+	// sines with RMS 0.0707 and 0.5657, both should converge to the target
+	// unless the gain ceiling is hit first
+	const target = 0.25
+	for _, test := range []struct{ amplitude, maxGain, expected float64 }{
+		{ 0.1, 10.0, target },
+		{ 0.8, 10.0, target },
+		{ 0.1,  2.0, 0.1*math.Sqrt2 },
+	} {
+		tone, err := io.ReadAll(NewTone(1000, test.amplitude, 2*time.Second, testSampleRate))
+		if err != nil { t.Fatal(err) }
+		agc := NewAGC(bytes.NewReader(tone), target, test.maxGain, 0.05, 0.1, testSampleRate)
+		output, err := io.ReadAll(agc)
+		if err != nil { t.Fatal(err) }
+		level := math.Max(MeasureRMS(output[len(output) - testSampleRate : ]))
+		if math.Abs(level - test.expected) > test.expected*0.05 {
+			t.Fatalf("amplitude %.1f, max gain %.0f: expected level %f, got %f", test.amplitude, test.maxGain, test.expected, level)
+		}
+	}
+}
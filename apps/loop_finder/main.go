@@ -4,7 +4,7 @@ import "io"
 import "os"
 import "fmt"
 import "log"
-import "errors"
+import "time"
 import "strconv"
 import "runtime"
 import "path/filepath"
@@ -36,15 +36,10 @@ func init() {
 // Notice that mp3's have padding at the start and the end that may hinder
 // your attempts to find loop points on other programs (like DAWs).
 const SampleRate = 44100 // only 44100 or 48000 expected
-const PlaybackPreMillis = 2300 // must end with two zeros
+const PlaybackPreRoll = 2300*time.Millisecond
 const SampleSize = 4 // this must not be changed, it's for clarity in code
 const BufferViewLen = 63 // must be odd
 
-// each time the loop points are moved, we re-read the relevant
-// portion of the stream to update the soundwave display. so, I
-// keep this reusable buffer globally for that task.
-var reuseBuffer = make([]byte, SampleRate*SampleSize)
-
 type Game struct {
 	looper *edau.Looper
 	filename string
@@ -66,7 +61,7 @@ func (self *Game) Update() error {
 	// handle space presses to start / stop the audio
 	if inpututil.IsKeyJustPressed(ebiten.KeySpace) {
 		if self.player == nil {
-			playbackStart := loopEnd - (PlaybackPreMillis*SampleRate*SampleSize)/1000
+			playbackStart := loopEnd - edau.BytesForDuration(PlaybackPreRoll, SampleRate)
 			if playbackStart < loopStart { playbackStart = loopStart }
 			self.looper.Seek(playbackStart, io.SeekStart)
 
@@ -113,8 +108,9 @@ func (self *Game) Update() error {
 			}
 		case 3: // end samples
 			newLoopEnd := loopEnd - SampleSize*change
-			if newLoopEnd < loopStart + SampleSize {
-				newLoopEnd = loopStart + SampleSize
+			minLoopLen := int64(self.looper.MinLoopFrames())*SampleSize
+			if newLoopEnd < loopStart + minLoopLen {
+				newLoopEnd = loopStart + minLoopLen
 			}
 			if newLoopEnd < loopEnd {
 				self.looper.AdjustLoop(loopStart, newLoopEnd)
@@ -134,8 +130,9 @@ func (self *Game) Update() error {
 			}
 		case 1: // start samples
 			newLoopStart := loopStart + SampleSize*change
-			if newLoopStart > loopEnd - SampleSize {
-				newLoopStart = loopEnd - SampleSize
+			minLoopLen := int64(self.looper.MinLoopFrames())*SampleSize
+			if newLoopStart > loopEnd - minLoopLen {
+				newLoopStart = loopEnd - minLoopLen
 			}
 			if newLoopStart > loopStart {
 				self.looper.AdjustLoop(newLoopStart, loopEnd)
@@ -169,67 +166,17 @@ func (self *Game) Update() error {
 }
 
 func (self *Game) refreshViewBuffers() {
-	// joint view
 	start, end := self.looper.GetLoopPoints()
-	leftLim, rightLim := start, end
-	
-	// read the start buffer (shown on the right)
-	from := start
-	self.readSection(&self.bufferStart, from, leftLim, rightLim)
-	
-	// read the end buffer (shown on the left)
-	from = end - BufferViewLen*SampleSize
-	self.readSection(&self.bufferEnd, from, leftLim, rightLim)
-}
 
-func (self *Game) readSection(buffer *[BufferViewLen][2]float64, start int64, leftLim, rightLim int64) {
-	end := start + BufferViewLen*SampleSize
-	missingBytes := int64(0)
-	
-	// apply left limit
-	i := 0
-	if start < leftLim {
-		missingBytes = leftLim - start
-		for missingBytes > 0 {
-			buffer[i] = [2]float64{ 0, 0 }
-			missingBytes -= 4
-			i += 1
-		}
-		missingBytes = 0
-	}
-	
-	// adjust based on right limit (will be applied at the end)
-	if end > rightLim {
-		missingBytes = rightLim - end
-		end = rightLim
-	}
-
-	// seek and read
-	nSeek, err := self.looper.Seek(start, io.SeekStart)
+	// read the start buffer (shown on the right)
+	frames, err := self.looper.PeekAround(start, 0, BufferViewLen)
 	if err != nil { panic(err) }
-	if nSeek != start { panic("n != start") }
-	bytesToRead := end - start
-	readBuffer := reuseBuffer[0 : bytesToRead] 
-	nRead, err := self.looper.Read(readBuffer)
-	if err != nil {
-		if errors.Is(err, io.EOF) && int64(nRead) != bytesToRead {
-			panic(err)
-		}
-	}
-	if int64(nRead) != bytesToRead { panic("looper short read") }
-	for len(readBuffer) > 0 {
-		left, right := edau.GetSampleAsF64(readBuffer)
-		readBuffer = readBuffer[4:]
-		buffer[i]  = [2]float64{ left, right }
-		i += 1
-	}
+	copy(self.bufferStart[:], frames)
 
-	// apply right limit
-	for missingBytes > 0 {
-		buffer[i] = [2]float64{ 0, 0 }
-		missingBytes -= 4
-		i += 1
-	}
+	// read the end buffer (shown on the left)
+	frames, err = self.looper.PeekAround(end, BufferViewLen, 0)
+	if err != nil { panic(err) }
+	copy(self.bufferEnd[:], frames)
 }
 
 func (self *Game) Draw(screen *ebiten.Image) {
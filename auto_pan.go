@@ -0,0 +1,94 @@
+package edau
+
+import "io"
+import "sync"
+
+// An AutoPan wraps an audio stream and sweeps its stereo position back and
+// forth with a low frequency oscillator (LFO), using [EqualPowerPan] to
+// compute the channel gains. Each channel is scaled by its gain, so stereo
+// sources keep their image, only shifted towards one side or the other.
+//
+// The sweep starts at the center and moves towards the right first. The LFO
+// phase advances once per sample and is preserved across reads and seeks,
+// so parameter changes and reads never introduce clicks.
+type AutoPan struct {
+	mutex sync.Mutex
+	source io.Reader
+	depth float64
+	lfo *LFO
+}
+
+// Creates a new [AutoPan]. The rate must be in [0, sampleRate/2) and the
+// depth in [0, 1]. A depth of 0 keeps the audio at the center, while a depth
+// of 1 sweeps it all the way to the extremes. This method will panic if any
+// of the values are invalid.
+// If the sample rate is 0, [DefaultSampleRate] is used.
+func NewAutoPan(source io.Reader, rateHz, depth float64, sampleRate int) *AutoPan {
+	sampleRate = resolveSampleRate(sampleRate, "NewAutoPan")
+	assertUnitRangeParam("depth", depth)
+	lfo := NewLFO(LFOSine, rateHz, sampleRate)
+	lfo.SetPhase(0.25) // start at the center
+	return &AutoPan {
+		source: source,
+		lfo: lfo,
+		depth: depth,
+	}
+}
+
+// Returns the underlying source.
+func (self *AutoPan) Source() io.Reader {
+	return self.source
+}
+
+// Returns the currently configured LFO rate, in Hz.
+func (self *AutoPan) Rate() float64 {
+	return self.lfo.Rate()
+}
+
+// Sets the LFO rate, in Hz. This method will panic if the value
+// is negative or above the Nyquist frequency.
+func (self *AutoPan) SetRate(rateHz float64) {
+	self.lfo.SetRate(rateHz)
+}
+
+// Returns the currently configured depth.
+func (self *AutoPan) Depth() float64 {
+	self.mutex.Lock()
+	depth := self.depth
+	self.mutex.Unlock()
+	return depth
+}
+
+// Sets the depth, which must be in [0, 1]. This method will
+// panic if the value is invalid.
+func (self *AutoPan) SetDepth(depth float64) {
+	assertUnitRangeParam("depth", depth)
+	self.mutex.Lock()
+	self.depth = depth
+	self.mutex.Unlock()
+}
+
+// Implements [io.Reader]. The returned read length will always be
+// multiple of 4, aligning to Ebitengine's sample size.
+func (self *AutoPan) Read(buffer []byte) (int, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	n, err := readFrames(self.source, buffer)
+	for i := 0; i < n; i += 4 {
+		leftGain, rightGain := EqualPowerPan(self.depth*self.lfo.Next())
+		left, right := GetSampleAsF64(buffer[i : ])
+		StoreNormF64SampleAsL16(buffer[i : ], left*leftGain, right*rightGain)
+	}
+	return n, err
+}
+
+// Implements [io.Seeker]. The LFO phase is not reset.
+//
+// If the underlying source doesn't implement [io.Seeker], [ErrNotSeekable]
+// is returned.
+func (self *AutoPan) Seek(offset int64, whence int) (int64, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	return seekSource(self.source, offset, whence)
+}
@@ -0,0 +1,37 @@
+package edau
+
+import "io"
+import "math"
+import "bytes"
+import "testing"
+
+func TestAutoPan(t *testing.T) {
+	// one sweep per second over a second of audio: the sound starts
+	// centered, goes fully right at 0.25s and fully left at 0.75s
+	input := testSineL16(1000, 0.5, testSampleRate)
+	output, err := io.ReadAll(NewAutoPan(bytes.NewReader(input), 1.0, 1.0, testSampleRate))
+	if err != nil { t.Fatal(err) }
+	const blockSize = 441*4 // 10ms
+	levelsAt := func(seconds float64) (float64, float64) {
+		start := (int(seconds*testSampleRate) - 220)*4
+		return MeasureRMS(output[start : start + blockSize])
+	}
+
+	inLevel, _ := MeasureRMS(input[0 : blockSize])
+	for _, seconds := range []float64{ 0.1, 0.25, 0.5, 0.6, 0.75, 0.9 } {
+		left, right := levelsAt(seconds)
+		power := left*left + right*right
+		if math.Abs(power - inLevel*inLevel) > inLevel*inLevel*0.02 {
+			t.Fatalf("expected constant power at %.2fs (got %f, input %f)", seconds, power, inLevel*inLevel)
+		}
+	}
+	if left, right := levelsAt(0.25); left > right*0.05 {
+		t.Fatalf("expected audio on the right at 0.25s (levels %f, %f)", left, right)
+	}
+	if left, right := levelsAt(0.75); right > left*0.05 {
+		t.Fatalf("expected audio on the left at 0.75s (levels %f, %f)", left, right)
+	}
+	if left, right := levelsAt(0.5); math.Abs(left - right) > left*0.05 {
+		t.Fatalf("expected centered audio at 0.5s (levels %f, %f)", left, right)
+	}
+}
@@ -0,0 +1,147 @@
+package edau
+
+import "io"
+import "sort"
+import "sync"
+import "time"
+
+// An Automation wraps an audio stream and changes its volume over time
+// following a series of scheduled gain points. Between points, the gain
+// is linearly interpolated. Before the first point, the gain of the first
+// point is used, and after the last point, its gain is held. Without any
+// points, the audio is left unmodified.
+//
+// The playback time is tracked through the number of samples read, and
+// it's updated on seeks.
+type Automation struct {
+	mutex sync.Mutex
+	source io.Reader
+	sampleRate int
+	position int64 // in samples
+	points []automationPoint // sorted by sample
+}
+
+type automationPoint struct {
+	sample int64
+	gain float64
+}
+
+// Creates a new [Automation] without any points. If the sampleRate is 0,
+// [DefaultSampleRate] is used.
+func NewAutomation(source io.Reader, sampleRate int) *Automation {
+	sampleRate = resolveSampleRate(sampleRate, "NewAutomation")
+	return &Automation {
+		source: source,
+		sampleRate: sampleRate,
+	}
+}
+
+// Returns the underlying source.
+func (self *Automation) Source() io.Reader {
+	return self.source
+}
+
+// Schedules the given gain at the given playback time. If a point already
+// exists at the same time, its gain is replaced. The time and gain can't
+// be negative. This method will panic otherwise.
+func (self *Automation) AddPoint(at time.Duration, gain float64) {
+	if at < 0 { panic("Automation point time can't be negative") }
+	if gain < 0 { panic("Automation point gain can't be negative") }
+	sample := SamplesForDuration(at, self.sampleRate)
+
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	index := sort.Search(len(self.points), func(i int) bool {
+		return self.points[i].sample >= sample
+	})
+	if index < len(self.points) && self.points[index].sample == sample {
+		self.points[index].gain = gain
+		return
+	}
+	self.points = append(self.points, automationPoint{})
+	copy(self.points[index + 1 : ], self.points[index : ])
+	self.points[index] = automationPoint{ sample: sample, gain: gain }
+}
+
+// Removes all the scheduled points.
+func (self *Automation) ClearPoints() {
+	self.mutex.Lock()
+	self.points = self.points[ : 0]
+	self.mutex.Unlock()
+}
+
+// Returns the gain at the current playback position.
+func (self *Automation) Gain() float64 {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	index := sort.Search(len(self.points), func(i int) bool {
+		return self.points[i].sample > self.position
+	})
+	return self.gainAt(self.position, index)
+}
+
+// Implements [io.Reader]. The returned read length will always be
+// multiple of 4, aligning to Ebitengine's sample size.
+func (self *Automation) Read(buffer []byte) (int, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	n, err := readFrames(self.source, buffer)
+	if len(self.points) == 0 {
+		self.position += int64(n/4)
+		return n, err
+	}
+
+	// index of the first point after the current position
+	index := sort.Search(len(self.points), func(i int) bool {
+		return self.points[i].sample > self.position
+	})
+	data := buffer[0 : n]
+	for len(data) > 0 {
+		// constant gain regions can be processed at once
+		if index == 0 || index == len(self.points) {
+			frames := int64(len(data)/4)
+			if index == 0 && self.points[0].sample - self.position < frames {
+				frames = self.points[0].sample - self.position
+			}
+			ApplyGain(data[0 : frames*4], self.gainAt(self.position, index))
+			data = data[frames*4 : ]
+			self.position += frames
+			if index == 0 && self.position >= self.points[0].sample { index += 1 }
+			continue
+		}
+
+		// interpolate between points
+		left, right := GetSampleAsF64(data)
+		gain := self.gainAt(self.position, index)
+		StoreNormF64SampleAsL16(data, left*gain, right*gain)
+		data = data[4 : ]
+		self.position += 1
+		for index < len(self.points) && self.points[index].sample <= self.position { index += 1 }
+	}
+	return n, err
+}
+
+// Implements [io.Seeker]. The playback time used for the automation
+// is updated based on the resulting position.
+//
+// If the underlying source doesn't implement [io.Seeker], [ErrNotSeekable]
+// is returned.
+func (self *Automation) Seek(offset int64, whence int) (int64, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	position, err := seekSource(self.source, offset, whence)
+	if err == nil { self.position = position/4 }
+	return position, err
+}
+
+// Returns the gain at the given sample. The index must be
+// the index of the first point after the sample.
+func (self *Automation) gainAt(sample int64, index int) float64 {
+	if len(self.points) == 0 { return 1.0 }
+	if index == 0 { return self.points[0].gain }
+	if index == len(self.points) { return self.points[index - 1].gain }
+	prev, next := self.points[index - 1], self.points[index]
+	t := float64(sample - prev.sample)/float64(next.sample - prev.sample)
+	return prev.gain + (next.gain - prev.gain)*t
+}
@@ -0,0 +1,113 @@
+package edau
+
+import "io"
+import "sync"
+
+// A Bitcrusher wraps an audio stream and reduces its effective bit depth
+// and sample rate, producing the characteristic lo-fi sound of old consoles
+// and samplers.
+//
+// The bit depth reduction is done by discarding the least significant bits
+// of each sample, and the sample rate reduction by holding each kept sample
+// during the next downsampleFactor - 1 samples.
+type Bitcrusher struct {
+	mutex sync.Mutex
+	source io.Reader
+	bits int
+	downsampleFactor int
+	holdCount int
+	heldLeft int16
+	heldRight int16
+}
+
+// Creates a new [Bitcrusher]. The bits must be in [1, 16] (16 meaning no
+// bit depth reduction) and the downsampleFactor must be at least 1 (1 meaning
+// no sample rate reduction). This method will panic otherwise.
+func NewBitcrusher(source io.Reader, bits int, downsampleFactor int) *Bitcrusher {
+	assertBitsValidity(bits)
+	assertDownsampleFactorValidity(downsampleFactor)
+	return &Bitcrusher {
+		source: source,
+		bits: bits,
+		downsampleFactor: downsampleFactor,
+	}
+}
+
+// Returns the underlying source.
+func (self *Bitcrusher) Source() io.Reader {
+	return self.source
+}
+
+// Returns the currently configured bit depth.
+func (self *Bitcrusher) Bits() int {
+	self.mutex.Lock()
+	bits := self.bits
+	self.mutex.Unlock()
+	return bits
+}
+
+// Sets the bit depth, which must be in [1, 16]. This method will
+// panic if the value is invalid.
+func (self *Bitcrusher) SetBits(bits int) {
+	assertBitsValidity(bits)
+	self.mutex.Lock()
+	self.bits = bits
+	self.mutex.Unlock()
+}
+
+// Returns the currently configured downsample factor.
+func (self *Bitcrusher) DownsampleFactor() int {
+	self.mutex.Lock()
+	downsampleFactor := self.downsampleFactor
+	self.mutex.Unlock()
+	return downsampleFactor
+}
+
+// Sets the downsample factor, which must be at least 1. This method
+// will panic if the value is invalid.
+func (self *Bitcrusher) SetDownsampleFactor(downsampleFactor int) {
+	assertDownsampleFactorValidity(downsampleFactor)
+	self.mutex.Lock()
+	self.downsampleFactor = downsampleFactor
+	self.mutex.Unlock()
+}
+
+// Implements [io.Reader]. The returned read length will always be
+// multiple of 4, aligning to Ebitengine's sample size.
+func (self *Bitcrusher) Read(buffer []byte) (int, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	mask := int16(-1) << (16 - self.bits)
+	n, err := readFrames(self.source, buffer)
+	for i := 0; i < n; i += 4 {
+		if self.holdCount <= 0 {
+			left, right := GetSampleAsI16(buffer[i : ])
+			self.heldLeft, self.heldRight = left & mask, right & mask
+			self.holdCount = self.downsampleFactor
+		}
+		StoreL16Sample(buffer[i : ], self.heldLeft, self.heldRight)
+		self.holdCount -= 1
+	}
+	return n, err
+}
+
+// Implements [io.Seeker].
+//
+// If the underlying source doesn't implement [io.Seeker], [ErrNotSeekable]
+// is returned.
+func (self *Bitcrusher) Seek(offset int64, whence int) (int64, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	position, err := seekSource(self.source, offset, whence)
+	self.holdCount = 0
+	return position, err
+}
+
+func assertBitsValidity(bits int) {
+	if bits < 1 || bits > 16 { panic("bits must be in [1, 16]") }
+}
+
+func assertDownsampleFactorValidity(downsampleFactor int) {
+	if downsampleFactor < 1 { panic("downsampleFactor must be at least 1") }
+}
@@ -0,0 +1,139 @@
+package edau
+
+import "io"
+import "sync"
+
+// Channel routing modes for the [ChannelRouter].
+type ChannelMode uint8
+const (
+	ChannelStereo ChannelMode = iota // channels are left untouched
+	ChannelSwapLR // left and right channels are swapped
+	ChannelLeftToBoth // the left channel is played on both channels
+	ChannelRightToBoth // the right channel is played on both channels
+	ChannelMatrix // channels are mixed with a custom matrix, see [NewChannelMatrix]
+)
+
+// A ChannelRouter wraps an audio stream and remaps its channels. This can
+// be used to fix recordings with reversed channels, to route all the audio
+// to a single ear for accessibility, or with a custom matrix, to mix the
+// channels in arbitrary proportions.
+type ChannelRouter struct {
+	mutex sync.Mutex
+	source io.Reader
+	mode ChannelMode
+	matrix [2][2]float64
+}
+
+// Creates a new [ChannelRouter] with the given mode. The mode can't be
+// [ChannelMatrix], see [NewChannelMatrix] for that instead. This method
+// will panic if the mode is invalid.
+func NewChannelRouter(source io.Reader, mode ChannelMode) *ChannelRouter {
+	assertChannelModeValidity(mode)
+	return &ChannelRouter{ source: source, mode: mode }
+}
+
+// Creates a new [ChannelRouter] that mixes the channels with the given
+// matrix. For each frame, the output channels are computed as:
+//   left  = matrix[0][0]*left + matrix[0][1]*right
+//   right = matrix[1][0]*left + matrix[1][1]*right
+// For example, {{0.5, 0.5}, {0.5, 0.5}} would downmix to mono, and
+// {{1, 0}, {0, 0}} would mute the right channel. Results out of range
+// are clipped.
+func NewChannelMatrix(source io.Reader, matrix [2][2]float64) *ChannelRouter {
+	return &ChannelRouter{ source: source, mode: ChannelMatrix, matrix: matrix }
+}
+
+// Returns the underlying source.
+func (self *ChannelRouter) Source() io.Reader {
+	return self.source
+}
+
+// Returns the current routing mode.
+func (self *ChannelRouter) Mode() ChannelMode {
+	self.mutex.Lock()
+	mode := self.mode
+	self.mutex.Unlock()
+	return mode
+}
+
+// Sets the routing mode. The mode can't be [ChannelMatrix], use
+// [ChannelRouter.SetMatrix] for that instead. This method will panic
+// if the mode is invalid.
+func (self *ChannelRouter) SetMode(mode ChannelMode) {
+	assertChannelModeValidity(mode)
+	self.mutex.Lock()
+	self.mode = mode
+	self.mutex.Unlock()
+}
+
+// Returns the last matrix configured with [NewChannelMatrix] or
+// [ChannelRouter.SetMatrix]. The matrix is only used while the
+// mode is [ChannelMatrix].
+func (self *ChannelRouter) Matrix() [2][2]float64 {
+	self.mutex.Lock()
+	matrix := self.matrix
+	self.mutex.Unlock()
+	return matrix
+}
+
+// Sets the mixing matrix and changes the mode to [ChannelMatrix].
+// See [NewChannelMatrix] for details.
+func (self *ChannelRouter) SetMatrix(matrix [2][2]float64) {
+	self.mutex.Lock()
+	self.matrix = matrix
+	self.mode = ChannelMatrix
+	self.mutex.Unlock()
+}
+
+// Implements [io.Reader]. The returned read length will always be
+// multiple of 4, aligning to Ebitengine's sample size.
+func (self *ChannelRouter) Read(buffer []byte) (int, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	n, err := readFrames(self.source, buffer)
+	switch self.mode {
+	case ChannelStereo:
+		// nothing to do
+	case ChannelSwapLR:
+		for i := 0; i < n; i += 4 {
+			left, right := GetSampleAsI16(buffer[i : ])
+			StoreL16Sample(buffer[i : ], right, left)
+		}
+	case ChannelLeftToBoth:
+		for i := 0; i < n; i += 4 {
+			left, _ := GetSampleAsI16(buffer[i : ])
+			StoreL16Sample(buffer[i : ], left, left)
+		}
+	case ChannelRightToBoth:
+		for i := 0; i < n; i += 4 {
+			_, right := GetSampleAsI16(buffer[i : ])
+			StoreL16Sample(buffer[i : ], right, right)
+		}
+	case ChannelMatrix:
+		m := &self.matrix
+		for i := 0; i < n; i += 4 {
+			left, right := GetSampleAsF64(buffer[i : ])
+			outLeft  := m[0][0]*left + m[0][1]*right
+			outRight := m[1][0]*left + m[1][1]*right
+			StoreNormF64SampleAsL16(buffer[i : ], outLeft, outRight)
+		}
+	default:
+		panic("invalid ChannelMode")
+	}
+	return n, err
+}
+
+// Implements [io.Seeker]. Channel routing is stateless, so this simply
+// seeks the underlying source.
+//
+// If the underlying source doesn't implement [io.Seeker], [ErrNotSeekable]
+// is returned.
+func (self *ChannelRouter) Seek(offset int64, whence int) (int64, error) {
+	return seekSource(self.source, offset, whence)
+}
+
+func assertChannelModeValidity(mode ChannelMode) {
+	if mode == ChannelMatrix { panic("ChannelMatrix mode can only be set through a matrix") }
+	if mode > ChannelMatrix { panic("invalid ChannelMode") }
+}
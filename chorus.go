@@ -0,0 +1,145 @@
+package edau
+
+import "io"
+import "math"
+import "sync"
+
+const chorusBaseDelayMs = 15.0
+const chorusMaxDepthMs  = 20.0
+
+// A Chorus wraps an audio stream and mixes it with multiple delayed copies
+// of itself (voices), each with its delay slowly modulated by a low frequency
+// oscillator (LFO). The slight and varying detuning of the voices results in
+// a thicker sound, which works great for pads and ambient tracks.
+//
+// All voices share the same LFO rate and depth, but their phases are evenly
+// distributed so they never align. Like in [Vibrato], the fractional delays
+// are interpolated with [InterpHermite6Pt3Ord].
+type Chorus struct {
+	mutex sync.Mutex
+	source io.Reader
+	sampleRate int
+	depth float64 // in samples
+	mix float64
+	lfo *LFO
+	voices int
+	line fractionalDelay
+}
+
+// Creates a new [Chorus]. The number of voices must be at least 1, the rate
+// must be in [0, sampleRate/2), the depth (maximum delay variation, in
+// milliseconds) must be in [0, 20] and the mix in [0, 1]. Typical values
+// are around 3 voices, 0.5Hz, 3ms and 0.5 mix. This method will panic if
+// any of the values are invalid.
+// If the sample rate is 0, [DefaultSampleRate] is used.
+func NewChorus(source io.Reader, voices int, rateHz, depthMs, mix float64, sampleRate int) *Chorus {
+	sampleRate = resolveSampleRate(sampleRate, "NewChorus")
+	if voices < 1 { panic("NewChorus voices must be at least 1") }
+	assertChorusDepthValidity(depthMs)
+	assertMixValidity(mix)
+	maxDelay := msToFrames(chorusBaseDelayMs + chorusMaxDepthMs, sampleRate)
+	return &Chorus {
+		source: source,
+		sampleRate: sampleRate,
+		lfo: NewLFO(LFOSine, rateHz, sampleRate),
+		depth: msToFrames(depthMs, sampleRate),
+		mix: mix,
+		voices: voices,
+		line: newFractionalDelay(int(math.Ceil(maxDelay)), InterpHermite6Pt3Ord, 6),
+	}
+}
+
+// Returns the underlying source.
+func (self *Chorus) Source() io.Reader {
+	return self.source
+}
+
+// Returns the currently configured LFO rate, in Hz.
+func (self *Chorus) Rate() float64 {
+	return self.lfo.Rate()
+}
+
+// Sets the LFO rate, in Hz. This method will panic if the value
+// is negative or above the Nyquist frequency.
+func (self *Chorus) SetRate(rateHz float64) {
+	self.lfo.SetRate(rateHz)
+}
+
+// Returns the currently configured depth, in milliseconds.
+func (self *Chorus) Depth() float64 {
+	self.mutex.Lock()
+	depth := (self.depth*1000.0)/float64(self.sampleRate)
+	self.mutex.Unlock()
+	return depth
+}
+
+// Sets the depth, in milliseconds. The value must be in [0, 20].
+// This method will panic if the value is invalid.
+func (self *Chorus) SetDepth(depthMs float64) {
+	assertChorusDepthValidity(depthMs)
+	self.mutex.Lock()
+	self.depth = msToFrames(depthMs, self.sampleRate)
+	self.mutex.Unlock()
+}
+
+// Returns the currently configured mix factor.
+func (self *Chorus) Mix() float64 {
+	self.mutex.Lock()
+	mix := self.mix
+	self.mutex.Unlock()
+	return mix
+}
+
+// Sets the mix factor, which must be in [0, 1]. 0 is fully dry and 1
+// fully wet. This method will panic if the value is invalid.
+func (self *Chorus) SetMix(mix float64) {
+	assertMixValidity(mix)
+	self.mutex.Lock()
+	self.mix = mix
+	self.mutex.Unlock()
+}
+
+// Implements [io.Reader]. The returned read length will always be
+// multiple of 4, aligning to Ebitengine's sample size.
+func (self *Chorus) Read(buffer []byte) (int, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	baseDelay := self.line.MinDelay() + msToFrames(chorusBaseDelayMs, self.sampleRate)
+	voiceGain := self.mix/float64(self.voices)
+	n, err := readFrames(self.source, buffer)
+	for i := 0; i < n; i += 4 {
+		left, right := GetSampleAsF64(buffer[i : ])
+		self.line.Push(left, right)
+
+		outLeft, outRight := left*(1.0 - self.mix), right*(1.0 - self.mix)
+		for v := 0; v < self.voices; v++ {
+			lfo := self.lfo.At(float64(v)/float64(self.voices))
+			delay := baseDelay + self.depth*(0.5 + 0.5*lfo)
+			voiceLeft, voiceRight := self.line.Tap(delay)
+			outLeft  += voiceLeft*voiceGain
+			outRight += voiceRight*voiceGain
+		}
+		StoreNormF64SampleAsL16(buffer[i : ], outLeft, outRight)
+
+		self.lfo.Advance()
+	}
+	return n, err
+}
+
+// Implements [io.Seeker]. The delay line is cleared after seeking,
+// but the LFO phase is not reset.
+//
+// If the underlying source doesn't implement [io.Seeker], [ErrNotSeekable]
+// is returned.
+func (self *Chorus) Seek(offset int64, whence int) (int64, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	position, err := seekSource(self.source, offset, whence)
+	self.line.Reset()
+	return position, err
+}
+
+func assertChorusDepthValidity(depthMs float64) {
+	if depthMs < 0 || depthMs > chorusMaxDepthMs { panic("chorus depth must be in [0, 20] milliseconds") }
+}
@@ -0,0 +1,47 @@
+package edau
+
+import "sync/atomic"
+
+import "github.com/hajimehoshi/ebiten/v2/audio"
+
+var defaultSampleRate int64 // accessed atomically, 0 if not set
+
+// Sets the sample rate used by constructors when they are given a
+// sampleRate of 0. For example, with a default of 48000, the following
+// calls are equivalent:
+//    edau.NewTremolo(source, 4.0, 0.5, 48000)
+//    edau.NewTremolo(source, 4.0, 0.5, 0)
+// Setting the default to 0 goes back to using the sample rate of
+// Ebitengine's audio.CurrentContext(). This function will panic if
+// the sample rate is negative.
+//
+// The setting is global and only affects streams created after the call.
+// Most games only have a single audio context, so the default from the
+// context is typically all that's needed.
+func SetDefaultSampleRate(sampleRate int) {
+	if sampleRate < 0 { panic("SetDefaultSampleRate sampleRate can't be negative") }
+	atomic.StoreInt64(&defaultSampleRate, int64(sampleRate))
+}
+
+// Returns the sample rate set with [SetDefaultSampleRate], or the sample
+// rate of Ebitengine's audio.CurrentContext() if none has been set. If
+// there's no audio context either, 0 is returned.
+func DefaultSampleRate() int {
+	sampleRate := int(atomic.LoadInt64(&defaultSampleRate))
+	if sampleRate > 0 { return sampleRate }
+	ctx := audio.CurrentContext()
+	if ctx == nil { return 0 }
+	return ctx.SampleRate()
+}
+
+// Returns the given sample rate, or [DefaultSampleRate] if it's zero.
+// This function will panic if the sample rate is negative or if it's
+// zero and no default is available. The caller name is used in the
+// panic messages.
+func resolveSampleRate(sampleRate int, caller string) int {
+	if sampleRate > 0 { return sampleRate }
+	if sampleRate < 0 { panic(caller + " sampleRate can't be negative") }
+	sampleRate = DefaultSampleRate()
+	if sampleRate == 0 { panic(caller + " sampleRate is 0, but there's no default sample rate nor audio context") }
+	return sampleRate
+}
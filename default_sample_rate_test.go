@@ -0,0 +1,25 @@
+package edau
+
+import "io"
+import "time"
+import "strings"
+import "testing"
+
+func TestDefaultSampleRate(t *testing.T) {
+	SetDefaultSampleRate(22050)
+	defer SetDefaultSampleRate(0)
+	if DefaultSampleRate() != 22050 { t.Fatalf("expected default sample rate 22050, got %d", DefaultSampleRate()) }
+	data, err := io.ReadAll(NewSilence(time.Second, 0))
+	if err != nil { t.Fatal(err) }
+	if len(data) != 22050*4 { t.Fatalf("expected %d bytes of silence, got %d", 22050*4, len(data)) }
+}
+
+func TestDefaultSampleRateMissing(t *testing.T) {
+	if DefaultSampleRate() != 0 { t.Skip("audio context initialized, a default sample rate is available") }
+	defer func() {
+		msg, isString := recover().(string)
+		if !isString { t.Fatal("expected a panic with a string message") }
+		if !strings.HasPrefix(msg, "NewSilence ") { t.Fatalf("expected the caller in the message, got %q", msg) }
+	}()
+	NewSilence(time.Second, 0)
+}
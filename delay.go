@@ -0,0 +1,146 @@
+package edau
+
+import "io"
+import "sync"
+import "time"
+
+// A Delay wraps an audio stream and adds echoes to it. Each output sample
+// is the original (dry) sample plus the delayed signal scaled by the mix
+// factor. The delayed signal is fed back into the delay line scaled by
+// the feedback factor, which produces repeating echoes that fade out.
+//
+// The delayed samples are stored in L16 format, so memory usage is 4 bytes
+// per sample of delay, e.g., 1 second of delay at 44.1kHz uses ~172KiB.
+type Delay struct {
+	mutex sync.Mutex
+	source io.Reader
+	feedback float64
+	mix float64
+	line delayLine
+}
+
+// Creates a new [Delay]. The delay must be at least one sample long,
+// feedback must be in [0, 1) and mix in [0, 1]. This method will panic
+// otherwise. If the sample rate is 0, [DefaultSampleRate] is used.
+func NewDelay(source io.Reader, delay time.Duration, feedback, mix float64, sampleRate int) *Delay {
+	sampleRate = resolveSampleRate(sampleRate, "NewDelay")
+	frames := int(SamplesForDuration(delay, sampleRate))
+	if frames < 1 { panic("NewDelay delay must be at least one sample long") }
+	assertFeedbackValidity(feedback)
+	assertMixValidity(mix)
+	return &Delay {
+		source: source,
+		feedback: feedback,
+		mix: mix,
+		line: newDelayLine(frames),
+	}
+}
+
+// Returns the underlying source.
+func (self *Delay) Source() io.Reader {
+	return self.source
+}
+
+// Returns the currently configured feedback factor.
+func (self *Delay) Feedback() float64 {
+	self.mutex.Lock()
+	feedback := self.feedback
+	self.mutex.Unlock()
+	return feedback
+}
+
+// Sets the feedback factor, which must be in [0, 1). Higher values make
+// the echoes last longer. This method will panic if the value is invalid.
+func (self *Delay) SetFeedback(feedback float64) {
+	assertFeedbackValidity(feedback)
+	self.mutex.Lock()
+	self.feedback = feedback
+	self.mutex.Unlock()
+}
+
+// Returns the currently configured mix factor.
+func (self *Delay) Mix() float64 {
+	self.mutex.Lock()
+	mix := self.mix
+	self.mutex.Unlock()
+	return mix
+}
+
+// Sets the mix factor, which must be in [0, 1]. This is the volume of
+// the echoes relative to the dry signal. This method will panic if the
+// value is invalid.
+func (self *Delay) SetMix(mix float64) {
+	assertMixValidity(mix)
+	self.mutex.Lock()
+	self.mix = mix
+	self.mutex.Unlock()
+}
+
+// Implements [io.Reader]. The returned read length will always be
+// multiple of 4, aligning to Ebitengine's sample size.
+func (self *Delay) Read(buffer []byte) (int, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	n, err := readFrames(self.source, buffer)
+	for i := 0; i < n; i += 4 {
+		left, right := GetSampleAsF64(buffer[i : ])
+		echoLeft, echoRight := self.line.Peek()
+		self.line.Push(left + self.feedback*echoLeft, right + self.feedback*echoRight)
+		StoreNormF64SampleAsL16(buffer[i : ], left + self.mix*echoLeft, right + self.mix*echoRight)
+	}
+	return n, err
+}
+
+// Implements [io.Seeker]. The delay line is cleared after seeking.
+//
+// If the underlying source doesn't implement [io.Seeker], [ErrNotSeekable]
+// is returned.
+func (self *Delay) Seek(offset int64, whence int) (int64, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	position, err := seekSource(self.source, offset, whence)
+	self.line.Reset()
+	return position, err
+}
+
+// --- helper delayLine type ---
+
+// A fixed-length delay line that stores L16 samples in a circular
+// byte buffer. Values are normalized to [-1, 1] when read, and clipped
+// to that range when stored.
+type delayLine struct {
+	buffer []byte
+	index int // position of the oldest sample
+}
+
+func newDelayLine(frames int) delayLine {
+	return delayLine{ buffer: make([]byte, frames*4) }
+}
+
+// Returns the oldest sample in the delay line.
+func (self *delayLine) Peek() (float64, float64) {
+	return GetSampleAsF64(self.buffer[self.index : ])
+}
+
+// Overwrites the oldest sample in the delay line with the given one,
+// which becomes the newest sample.
+func (self *delayLine) Push(left, right float64) {
+	StoreNormF64SampleAsL16(self.buffer[self.index : ], left, right)
+	self.index += 4
+	if self.index >= len(self.buffer) { self.index = 0 }
+}
+
+// Fills the delay line with silence.
+func (self *delayLine) Reset() {
+	for i := range self.buffer { self.buffer[i] = 0 }
+	self.index = 0
+}
+
+func assertFeedbackValidity(feedback float64) {
+	if feedback < 0 || feedback >= 1.0 { panic("feedback must be in [0, 1)") }
+}
+
+func assertMixValidity(mix float64) {
+	if mix < 0 || mix > 1.0 { panic("mix must be in [0, 1]") }
+}
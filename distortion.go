@@ -0,0 +1,124 @@
+package edau
+
+import "io"
+import "math"
+import "sync"
+
+// Transfer curves for the [Distortion] effect.
+type DistortionCurve uint8
+const (
+	DistortionHardClip DistortionCurve = iota // clips the signal to [-1, 1], harsh
+	DistortionTanh // soft saturation, warm
+	DistortionArctan // soft saturation, slightly harsher than tanh
+)
+
+// A Distortion wraps an audio stream and applies a waveshaping function
+// to it. The input samples are scaled by the drive factor and then passed
+// through the selected [DistortionCurve].
+type Distortion struct {
+	mutex sync.Mutex
+	source io.Reader
+	drive float64
+	curve DistortionCurve
+}
+
+// Creates a new [Distortion]. The drive must be strictly positive, with
+// values above 1 pushing the signal further into the non-linear region of
+// the curve. This method will panic if the drive or the curve are invalid.
+func NewDistortion(source io.Reader, drive float64, curve DistortionCurve) *Distortion {
+	assertDriveValidity(drive)
+	assertDistortionCurveValidity(curve)
+	return &Distortion {
+		source: source,
+		drive: drive,
+		curve: curve,
+	}
+}
+
+// Returns the underlying source.
+func (self *Distortion) Source() io.Reader {
+	return self.source
+}
+
+// Returns the currently configured drive.
+func (self *Distortion) Drive() float64 {
+	self.mutex.Lock()
+	drive := self.drive
+	self.mutex.Unlock()
+	return drive
+}
+
+// Sets the drive, which must be strictly positive. This method will
+// panic if the value is invalid.
+func (self *Distortion) SetDrive(drive float64) {
+	assertDriveValidity(drive)
+	self.mutex.Lock()
+	self.drive = drive
+	self.mutex.Unlock()
+}
+
+// Returns the currently configured transfer curve.
+func (self *Distortion) Curve() DistortionCurve {
+	self.mutex.Lock()
+	curve := self.curve
+	self.mutex.Unlock()
+	return curve
+}
+
+// Sets the transfer curve. This method will panic if the curve is invalid.
+func (self *Distortion) SetCurve(curve DistortionCurve) {
+	assertDistortionCurveValidity(curve)
+	self.mutex.Lock()
+	self.curve = curve
+	self.mutex.Unlock()
+}
+
+// Implements [io.Reader]. The returned read length will always be
+// multiple of 4, aligning to Ebitengine's sample size.
+func (self *Distortion) Read(buffer []byte) (int, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	n, err := readFrames(self.source, buffer)
+	for i := 0; i < n; i += 4 {
+		left, right := GetSampleAsF64(buffer[i : ])
+		left  = self.shape(left*self.drive)
+		right = self.shape(right*self.drive)
+		StoreNormF64SampleAsL16(buffer[i : ], left, right)
+	}
+	return n, err
+}
+
+// Implements [io.Seeker]. Distortion is stateless, so this simply
+// seeks the underlying source.
+//
+// If the underlying source doesn't implement [io.Seeker], [ErrNotSeekable]
+// is returned.
+func (self *Distortion) Seek(offset int64, whence int) (int64, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	return seekSource(self.source, offset, whence)
+}
+
+func (self *Distortion) shape(value float64) float64 {
+	switch self.curve {
+	case DistortionHardClip:
+		if value >  1.0 { return  1.0 }
+		if value < -1.0 { return -1.0 }
+		return value
+	case DistortionTanh:
+		return math.Tanh(value)
+	case DistortionArctan:
+		return (2.0/math.Pi)*math.Atan(value)
+	default:
+		panic("invalid DistortionCurve")
+	}
+}
+
+func assertDriveValidity(drive float64) {
+	if drive <= 0 { panic("drive must be strictly positive") }
+}
+
+func assertDistortionCurveValidity(curve DistortionCurve) {
+	if curve > DistortionArctan { panic("invalid DistortionCurve") }
+}
@@ -0,0 +1,67 @@
+package edau
+
+import "io"
+import "math"
+import "bytes"
+import "testing"
+
+func TestDistortionHarmonics(t *testing.T) {
+	const freq = 441.0 // whole number of cycles in one second
+	input := testSineL16(freq, 0.8, testSampleRate)
+	inputRatio := testHarmonicMagnitude(input, freq*3)/testHarmonicMagnitude(input, freq)
+	if inputRatio > 0.001 {
+		t.Fatalf("unexpected 3rd harmonic ratio on the clean sine (%f)", inputRatio)
+	}
+
+	for _, curve := range []DistortionCurve{ DistortionHardClip, DistortionTanh, DistortionArctan } {
+		distortion := NewDistortion(bytes.NewReader(input), 4.0, curve)
+		output, err := io.ReadAll(distortion)
+		if err != nil { t.Fatal(err) }
+		ratio := testHarmonicMagnitude(output, freq*3)/testHarmonicMagnitude(output, freq)
+		if ratio < 0.05 {
+			t.Fatalf("expected distortion curve %d to add a 3rd harmonic, but ratio is only %f", curve, ratio)
+		}
+	}
+}
+
+func TestSaturator(t *testing.T) {
+	// quiet signals must pass through untouched
+	quiet := testSineL16(441, 0.5, testSampleRate)
+	output, err := io.ReadAll(NewSaturator(bytes.NewReader(quiet), 1.0))
+	if err != nil { t.Fatal(err) }
+	if !bytes.Equal(quiet, output) { t.Fatal("expected quiet signal to pass through the saturator unchanged") }
+
+	// hot signals must be rounded off below full scale
+	hot := NewSaturator(bytes.NewReader(quiet), 3.0)
+	output, err = io.ReadAll(hot)
+	if err != nil { t.Fatal(err) }
+	peakL, _ := MeasurePeak(output)
+	if peakL < 0.9 || peakL >= 1.0 {
+		t.Fatalf("expected saturated peak in [0.9, 1.0), got %f", peakL)
+	}
+
+	// the curve must be monotonic and continuous at the knee
+	prev := saturate(0)
+	for value := 0.001; value < 5.0; value += 0.001 {
+		curr := saturate(value)
+		if curr <= prev || curr - prev > 0.0011 {
+			t.Fatalf("saturation curve misbehaves at %f (%f -> %f)", value, prev, curr)
+		}
+		if saturate(-value) != -curr { t.Fatalf("saturation curve is not symmetric at %f", value) }
+		prev = curr
+	}
+}
+
+// Returns the magnitude of the given frequency on the left channel of
+// the given L16 buffer, computed through a single DFT bin.
+func testHarmonicMagnitude(buffer []byte, freq float64) float64 {
+	var re, im float64
+	numSamples := len(buffer)/4
+	for i := 0; i < numSamples; i++ {
+		left, _ := GetSampleAsF64(buffer[i*4 : ])
+		phase := 2*math.Pi*freq*float64(i)/testSampleRate
+		re += left*math.Cos(phase)
+		im -= left*math.Sin(phase)
+	}
+	return math.Hypot(re, im)/float64(numSamples)
+}
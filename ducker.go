@@ -0,0 +1,200 @@
+package edau
+
+import "io"
+import "math"
+import "sync"
+
+const duckerBlockFrames = 64 // ~1.5ms at 44.1kHz
+
+// A Ducker wraps a "carrier" audio stream and reduces its volume whenever
+// a separate "trigger" stream is loud enough, a technique known as sidechain
+// ducking. A typical use is lowering the music while a voice is playing.
+//
+// The trigger level is measured in blocks of 64 samples with [MeasureRMS],
+// and when it goes above the threshold, the carrier gain is reduced in the
+// same way a compressor would: with a ratio of 4, for example, each 4dB of
+// trigger level above the threshold result in 3dB of gain reduction. The
+// attack and release times control how fast the gain reduction is applied
+// and recovered.
+//
+// The trigger is only consumed for detection, it's not mixed into the output.
+// It's read at the same pace as the carrier, and once it reaches [io.EOF] it's
+// considered silent. If the trigger returns any other error, it's considered
+// silent for that read, and the error is returned along with the carrier data.
+type Ducker struct {
+	mutex sync.Mutex
+	carrier io.Reader
+	trigger io.Reader
+	triggerBuffer []byte
+	triggerDone bool
+	sampleRate int
+	threshold float64
+	ratio float64
+	attack float64
+	release float64
+	attackCoef float64
+	releaseCoef float64
+	gain float64
+}
+
+// Creates a new [Ducker]. The threshold is a normalized RMS level in (0, 1],
+// the ratio must be >= 1, and the attack and release times are given in
+// seconds and can't be negative. This method will panic if any of the values
+// are invalid. If the sample rate is 0, [DefaultSampleRate] is used.
+func NewDucker(carrier, trigger io.Reader, threshold, ratio, attack, release float64, sampleRate int) *Ducker {
+	sampleRate = resolveSampleRate(sampleRate, "NewDucker")
+	assertDuckerThresholdValidity(threshold)
+	assertDuckerRatioValidity(ratio)
+	assertDuckerTimeValidity(attack)
+	assertDuckerTimeValidity(release)
+	return &Ducker {
+		carrier: carrier,
+		trigger: trigger,
+		sampleRate: sampleRate,
+		threshold: threshold,
+		ratio: ratio,
+		attack: attack,
+		release: release,
+		attackCoef: smoothingCoef(attack, sampleRate),
+		releaseCoef: smoothingCoef(release, sampleRate),
+		gain: 1.0,
+	}
+}
+
+// Returns the currently configured threshold.
+func (self *Ducker) Threshold() float64 {
+	self.mutex.Lock()
+	threshold := self.threshold
+	self.mutex.Unlock()
+	return threshold
+}
+
+// Sets the threshold, which must be in (0, 1]. This method will
+// panic if the value is invalid.
+func (self *Ducker) SetThreshold(threshold float64) {
+	assertDuckerThresholdValidity(threshold)
+	self.mutex.Lock()
+	self.threshold = threshold
+	self.mutex.Unlock()
+}
+
+// Returns the currently configured ratio.
+func (self *Ducker) Ratio() float64 {
+	self.mutex.Lock()
+	ratio := self.ratio
+	self.mutex.Unlock()
+	return ratio
+}
+
+// Sets the ratio, which must be >= 1. This method will panic if the
+// value is invalid.
+func (self *Ducker) SetRatio(ratio float64) {
+	assertDuckerRatioValidity(ratio)
+	self.mutex.Lock()
+	self.ratio = ratio
+	self.mutex.Unlock()
+}
+
+// Returns the currently configured attack and release times, in seconds.
+func (self *Ducker) Times() (float64, float64) {
+	self.mutex.Lock()
+	attack, release := self.attack, self.release
+	self.mutex.Unlock()
+	return attack, release
+}
+
+// Sets the attack time, in seconds. This method will panic if
+// the value is negative.
+func (self *Ducker) SetAttack(attack float64) {
+	assertDuckerTimeValidity(attack)
+	self.mutex.Lock()
+	self.attack = attack
+	self.attackCoef = smoothingCoef(attack, self.sampleRate)
+	self.mutex.Unlock()
+}
+
+// Sets the release time, in seconds. This method will panic if
+// the value is negative.
+func (self *Ducker) SetRelease(release float64) {
+	assertDuckerTimeValidity(release)
+	self.mutex.Lock()
+	self.release = release
+	self.releaseCoef = smoothingCoef(release, self.sampleRate)
+	self.mutex.Unlock()
+}
+
+// Implements [io.Reader]. The returned read length will always be
+// multiple of 4, aligning to Ebitengine's sample size.
+func (self *Ducker) Read(buffer []byte) (int, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	n, err := readFrames(self.carrier, buffer)
+	if n == 0 { return n, err }
+
+	// read the same amount of data from the trigger
+	if cap(self.triggerBuffer) < n { self.triggerBuffer = make([]byte, n) }
+	triggerBuffer := self.triggerBuffer[0 : n]
+	triggerBytes := 0
+	if !self.triggerDone {
+		var triggerErr error
+		triggerBytes, triggerErr = readFrames(self.trigger, triggerBuffer)
+		if triggerErr == io.EOF {
+			self.triggerDone = true
+		} else if triggerErr != nil && err == nil {
+			// the carrier data has already been consumed, so it must
+			// still be returned, with the error reported alongside
+			err = triggerErr
+		}
+	}
+	for i := triggerBytes; i < n; i++ { triggerBuffer[i] = 0 }
+
+	// apply gain reduction block by block
+	for start := 0; start < n; start += duckerBlockFrames*4 {
+		end := start + duckerBlockFrames*4
+		if end > n { end = n }
+		target := self.targetGain(math.Max(MeasureRMS(triggerBuffer[start : end])))
+		for i := start; i < end; i += 4 {
+			if target < self.gain {
+				self.gain += (target - self.gain)*self.attackCoef
+			} else {
+				self.gain += (target - self.gain)*self.releaseCoef
+			}
+			left, right := GetSampleAsF64(buffer[i : ])
+			StoreNormF64SampleAsL16(buffer[i : ], left*self.gain, right*self.gain)
+		}
+	}
+	return n, err
+}
+
+// Implements [io.Seeker]. Only the carrier is seeked, the trigger keeps
+// playing independently. The gain reduction is reset after seeking.
+//
+// If the carrier doesn't implement [io.Seeker], [ErrNotSeekable] is returned.
+func (self *Ducker) Seek(offset int64, whence int) (int64, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	position, err := seekSource(self.carrier, offset, whence)
+	self.gain = 1.0
+	return position, err
+}
+
+// Returns the carrier gain corresponding to the given trigger level.
+func (self *Ducker) targetGain(level float64) float64 {
+	if level <= self.threshold { return 1.0 }
+	overDb := 20.0*math.Log10(level/self.threshold)
+	reductionDb := overDb*(1.0 - 1.0/self.ratio)
+	return math.Pow(10, -reductionDb/20.0)
+}
+
+func assertDuckerThresholdValidity(threshold float64) {
+	if threshold <= 0 || threshold > 1.0 { panic("ducker threshold must be in (0, 1]") }
+}
+
+func assertDuckerRatioValidity(ratio float64) {
+	if ratio < 1.0 { panic("ducker ratio must be >= 1") }
+}
+
+func assertDuckerTimeValidity(seconds float64) {
+	if seconds < 0 { panic("ducker attack and release times can't be negative") }
+}
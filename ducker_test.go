@@ -0,0 +1,32 @@
+package edau
+
+import "bytes"
+import "errors"
+import "testing"
+
+// A reader that returns the given error after the data is exhausted.
+type testFailingReader struct {
+	reader *bytes.Reader
+	err error
+}
+
+func (self *testFailingReader) Read(buffer []byte) (int, error) {
+	n, _ := self.reader.Read(buffer)
+	if self.reader.Len() == 0 { return n, self.err }
+	return n, nil
+}
+
+func TestDuckerTriggerError(t *testing.T) {
+	carrier := testSineL16(440, 0.5, testSampleRate)
+	triggerErr := errors.New("trigger failure")
+	trigger := &testFailingReader{ reader: bytes.NewReader(make([]byte, 4096)), err: triggerErr }
+	ducker := NewDucker(bytes.NewReader(carrier), trigger, 0.1, 4.0, 0.01, 0.1, testSampleRate)
+
+	buffer := make([]byte, 8192)
+	n, err := ducker.Read(buffer)
+	if err != triggerErr { t.Fatalf("expected the trigger error, got %v", err) }
+	if n != len(buffer) { t.Fatalf("expected %d bytes of carrier data, got %d", len(buffer), n) }
+	if !bytes.Equal(buffer[0 : n], carrier[0 : n]) {
+		t.Fatal("expected the carrier data unducked, as the trigger is silent")
+	}
+}
@@ -0,0 +1,141 @@
+package edau
+
+import "io"
+import "sync"
+import "time"
+
+type envelopeStage uint8
+const (
+	envelopeIdle envelopeStage = iota
+	envelopeAttack
+	envelopeDecay
+	envelopeSustain
+	envelopeRelease
+	envelopeDone
+)
+
+// An Envelope wraps an audio stream and applies an ADSR (attack, decay,
+// sustain, release) amplitude envelope to it:
+//  - Before [Envelope.Trigger] is called, silence is served and the
+//    underlying source is not read.
+//  - After [Envelope.Trigger], the gain goes linearly from 0 to 1 during
+//    the attack, and then from 1 to the sustain level during the decay.
+//  - The sustain level is held until [Envelope.Release] is called. Then,
+//    the gain goes linearly down to 0 during the release, and once it's
+//    finished, Read returns [io.EOF].
+//
+// This is the basic building block for instrument-like playback.
+type Envelope struct {
+	mutex sync.Mutex
+	source io.Reader
+	attackStep float64
+	decayStep float64
+	releaseFrames float64
+	releaseStep float64
+	sustain float64
+	gain float64
+	stage envelopeStage
+}
+
+// Creates a new [Envelope]. The sustain level must be in [0, 1], and the
+// durations can't be negative. This method will panic otherwise.
+// If the sample rate is 0, [DefaultSampleRate] is used.
+func NewEnvelope(source io.Reader, attack, decay time.Duration, sustain float64, release time.Duration, sampleRate int) *Envelope {
+	sampleRate = resolveSampleRate(sampleRate, "NewEnvelope")
+	if attack < 0 || decay < 0 || release < 0 { panic("NewEnvelope durations can't be negative") }
+	assertUnitRangeParam("sustain", sustain)
+	return &Envelope {
+		source: source,
+		attackStep: envelopeStep(1.0, attack, sampleRate),
+		decayStep: envelopeStep(1.0 - sustain, decay, sampleRate),
+		releaseFrames: release.Seconds()*float64(sampleRate),
+		sustain: sustain,
+	}
+}
+
+// Returns the underlying source.
+func (self *Envelope) Source() io.Reader {
+	return self.source
+}
+
+// Starts the attack stage. If the envelope was already active, the attack
+// starts from the current gain level instead of 0, so there are no clicks.
+func (self *Envelope) Trigger() {
+	self.mutex.Lock()
+	self.stage = envelopeAttack
+	self.mutex.Unlock()
+}
+
+// Starts the release stage. Once the release finishes, Read will return
+// [io.EOF]. If the envelope was never triggered, it finishes immediately.
+func (self *Envelope) Release() {
+	self.mutex.Lock()
+	switch self.stage {
+	case envelopeIdle:
+		self.stage = envelopeDone
+	case envelopeAttack, envelopeDecay, envelopeSustain:
+		self.stage = envelopeRelease
+		self.releaseStep = 1.0
+		if self.releaseFrames >= 1.0 {
+			self.releaseStep = self.gain/self.releaseFrames
+		}
+	}
+	self.mutex.Unlock()
+}
+
+// Implements [io.Reader]. The returned read length will always be
+// multiple of 4, aligning to Ebitengine's sample size.
+func (self *Envelope) Read(buffer []byte) (int, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	buffer = buffer[0 : len(buffer) - (len(buffer) & 0b11)]
+	switch self.stage {
+	case envelopeDone:
+		return 0, io.EOF
+	case envelopeIdle:
+		for i := range buffer { buffer[i] = 0 }
+		return len(buffer), nil
+	}
+
+	n, err := readFrames(self.source, buffer)
+	for i := 0; i < n; i += 4 {
+		self.advance()
+		if self.stage == envelopeDone { return i, io.EOF }
+		left, right := GetSampleAsF64(buffer[i : ])
+		StoreNormF64SampleAsL16(buffer[i : ], left*self.gain, right*self.gain)
+	}
+	return n, err
+}
+
+// Advances the envelope by one sample.
+func (self *Envelope) advance() {
+	switch self.stage {
+	case envelopeAttack:
+		self.gain += self.attackStep
+		if self.gain >= 1.0 {
+			self.gain = 1.0
+			self.stage = envelopeDecay
+		}
+	case envelopeDecay:
+		self.gain -= self.decayStep
+		if self.gain <= self.sustain {
+			self.gain = self.sustain
+			self.stage = envelopeSustain
+		}
+	case envelopeRelease:
+		self.gain -= self.releaseStep
+		if self.gain <= 0 {
+			self.gain = 0
+			self.stage = envelopeDone
+		}
+	}
+}
+
+// Returns the gain change per sample required to cover the
+// given distance in the given duration.
+func envelopeStep(distance float64, duration time.Duration, sampleRate int) float64 {
+	frames := duration.Seconds()*float64(sampleRate)
+	if frames < 1.0 { return distance }
+	return distance/frames
+}
@@ -0,0 +1,115 @@
+package edau
+
+import "io"
+import "sync"
+
+// An Equalizer wraps an audio stream and applies a series of peaking
+// filters to it, each boosting or cutting a band of frequencies. Bands
+// are added with [Equalizer.AddBand] and processed in series.
+//
+// An equalizer without bands leaves the audio unmodified.
+type Equalizer struct {
+	mutex sync.Mutex
+	source io.Reader
+	sampleRate int
+	bands []equalizerBand
+}
+
+type equalizerBand struct {
+	freq float64
+	q float64
+	gainDb float64
+	filter biquadFilter
+}
+
+// Creates a new [Equalizer] without any bands. If the sampleRate is 0,
+// [DefaultSampleRate] is used.
+func NewEqualizer(source io.Reader, sampleRate int) *Equalizer {
+	sampleRate = resolveSampleRate(sampleRate, "NewEqualizer")
+	return &Equalizer {
+		source: source,
+		sampleRate: sampleRate,
+	}
+}
+
+// Returns the underlying source.
+func (self *Equalizer) Source() io.Reader {
+	return self.source
+}
+
+// Adds a new band centered at the given frequency and returns its id,
+// which can be used with [Equalizer.SetBandGain]. Positive gains boost the
+// band and negative gains cut it. Higher q values make the band narrower.
+//
+// The frequency must be in (0, sampleRate/2) and q must be strictly positive.
+// This method will panic otherwise.
+func (self *Equalizer) AddBand(freq, gainDb, q float64) int {
+	assertFilterFreqValidity(freq, self.sampleRate)
+	assertFilterQValidity(q)
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	band := equalizerBand{ freq: freq, q: q, gainDb: gainDb }
+	band.filter.coeffs = newPeakingCoeffs(freq, q, gainDb, self.sampleRate)
+	self.bands = append(self.bands, band)
+	return len(self.bands) - 1
+}
+
+// Returns the currently configured gain for the given band, in dB.
+// This method will panic if the band id is invalid.
+func (self *Equalizer) BandGain(id int) float64 {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	self.assertBandIdValidity(id)
+	return self.bands[id].gainDb
+}
+
+// Sets the gain for the given band, in dB. The filter state is preserved,
+// so gains can be changed during playback. This method will panic if the
+// band id is invalid.
+func (self *Equalizer) SetBandGain(id int, gainDb float64) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	self.assertBandIdValidity(id)
+	band := &self.bands[id]
+	band.gainDb = gainDb
+	band.filter.coeffs = newPeakingCoeffs(band.freq, band.q, gainDb, self.sampleRate)
+}
+
+// Implements [io.Reader]. The returned read length will always be
+// multiple of 4, aligning to Ebitengine's sample size.
+func (self *Equalizer) Read(buffer []byte) (int, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	n, err := readFrames(self.source, buffer)
+	if len(self.bands) == 0 { return n, err }
+	for i := 0; i < n; i += 4 {
+		left, right := GetSampleAsF64(buffer[i : ])
+		for j := range self.bands {
+			filter := &self.bands[j].filter
+			left  = filter.left.Next(&filter.coeffs, left)
+			right = filter.right.Next(&filter.coeffs, right)
+		}
+		StoreNormF64SampleAsL16(buffer[i : ], left, right)
+	}
+	return n, err
+}
+
+// Implements [io.Seeker]. The state of all the band filters
+// is reset after seeking.
+//
+// If the underlying source doesn't implement [io.Seeker], [ErrNotSeekable]
+// is returned.
+func (self *Equalizer) Seek(offset int64, whence int) (int64, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	position, err := seekSource(self.source, offset, whence)
+	for i := range self.bands {
+		self.bands[i].filter.Reset()
+	}
+	return position, err
+}
+
+func (self *Equalizer) assertBandIdValidity(id int) {
+	if id < 0 || id >= len(self.bands) { panic("invalid equalizer band id") }
+}
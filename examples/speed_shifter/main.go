@@ -26,15 +26,12 @@ func (self *Game) Update() error {
 	change := 0.1
 	if ebiten.IsKeyPressed(ebiten.KeyShift) { change = 0.01 }
 
+	// speed limits are configured on the shifter itself, so no clamping is needed here
 	speed := self.audioSrc.Speed()
-	if inpututil.IsKeyJustPressed(ebiten.KeyArrowUp) && speed < 1.9 {
-		speed += change
-		if speed >= 1.9 { speed = 1.9 }
-		self.audioSrc.SetSpeed(speed)
-	} else if inpututil.IsKeyJustPressed(ebiten.KeyArrowDown) && speed > 0.1 {
-		speed -= change
-		if speed <= 0.1 { speed = 0.1 }
-		self.audioSrc.SetSpeed(speed)
+	if inpututil.IsKeyJustPressed(ebiten.KeyArrowUp) {
+		self.audioSrc.SetSpeed(speed + change)
+	} else if inpututil.IsKeyJustPressed(ebiten.KeyArrowDown) {
+		self.audioSrc.SetSpeed(speed - change)
 	}
 	return nil
 }
@@ -65,6 +62,7 @@ func main() {
 
 	// create speed shifter and start playing audio
 	shifter := edau.NewDefaultSpeedShifter(stream)
+	shifter.SetSpeedLimits(0.1, 1.9)
 	ebiten.SetWindowTitle("speed shifter")
 	player, err := ctx.NewPlayer(shifter)
 	if err != nil { log.Fatal(err) }
@@ -0,0 +1,42 @@
+package edau
+
+import "math"
+
+// Gain curves for fades and crossfades. See [FadeCurve.At].
+type FadeCurve uint8
+const (
+	FadeLinear FadeCurve = iota // constant gain slope, simple but the volume seems to change fast at the start
+	FadeEqualPower // quarter sine, keeps the power constant when crossfading uncorrelated sounds
+	FadeExponential // constant slope in dB over a 60dB range, sounds even to the ear
+	FadeSCurve // smoothstep, gentle at both ends and faster in the middle
+)
+
+// Returns the gain for the given fade-in progress, going from 0 at the
+// start to 1 at the end. Values outside [0, 1] are clamped. For fade-outs,
+// use At(1 - progress) instead. When crossfading two sounds, the incoming
+// one can use At(progress) and the outgoing one At(1 - progress).
+//
+// With [FadeLinear] and [FadeSCurve], the gains of both sides of a
+// crossfade always add up to 1, which works best for correlated sounds
+// (like two parts of the same loop). With [FadeEqualPower], their squares
+// add up to 1 instead, which avoids the dip in the middle of crossfades
+// between unrelated sounds.
+//
+// This method will panic if the curve is invalid.
+func (self FadeCurve) At(progress float64) float64 {
+	if progress < 0 { progress = 0 }
+	if progress > 1 { progress = 1 }
+	switch self {
+	case FadeLinear:
+		return progress
+	case FadeEqualPower:
+		return math.Sin(progress*math.Pi/2.0)
+	case FadeExponential:
+		// offset so the curve starts exactly at 0 instead of -60dB
+		return (math.Pow(1000.0, progress) - 1.0)/999.0
+	case FadeSCurve:
+		return progress*progress*(3.0 - 2.0*progress)
+	default:
+		panic("invalid FadeCurve")
+	}
+}
@@ -0,0 +1,36 @@
+package edau
+
+import "math"
+import "testing"
+
+func TestFadeCurve(t *testing.T) {
+	const eps = 0.000001
+	for _, curve := range []FadeCurve{ FadeLinear, FadeEqualPower, FadeExponential, FadeSCurve } {
+		if curve.At(0) != 0 || curve.At(-1) != 0 { t.Fatalf("curve %d: expected 0 at the start", curve) }
+		if math.Abs(curve.At(1) - 1) > eps || curve.At(2) != 1 {
+			t.Fatalf("curve %d: expected 1 at the end", curve)
+		}
+		prev := 0.0
+		for i := 1; i <= 100; i++ {
+			gain := curve.At(float64(i)/100)
+			if gain <= prev { t.Fatalf("curve %d: expected monotonic gains (%f after %f)", curve, gain, prev) }
+			prev = gain
+		}
+	}
+
+	// crossfade properties at the midpoint and elsewhere
+	for _, progress := range []float64{ 0.1, 0.3, 0.5, 0.85 } {
+		in, out := FadeLinear.At(progress), FadeLinear.At(1 - progress)
+		if math.Abs(in + out - 1) > eps { t.Fatalf("linear gains must add up to 1, got %f", in + out) }
+		in, out = FadeSCurve.At(progress), FadeSCurve.At(1 - progress)
+		if math.Abs(in + out - 1) > eps { t.Fatalf("s-curve gains must add up to 1, got %f", in + out) }
+		in, out = FadeEqualPower.At(progress), FadeEqualPower.At(1 - progress)
+		if math.Abs(in*in + out*out - 1) > eps {
+			t.Fatalf("equal power squared gains must add up to 1, got %f", in*in + out*out)
+		}
+	}
+
+	// the exponential curve should go up 6dB every 0.1 progress
+	ratio := (FadeExponential.At(0.9) + 1.0/999.0)/(FadeExponential.At(0.8) + 1.0/999.0)
+	if math.Abs(20*math.Log10(ratio) - 6) > eps { t.Fatalf("expected 6dB steps, got %f", 20*math.Log10(ratio)) }
+}
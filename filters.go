@@ -0,0 +1,359 @@
+package edau
+
+import "io"
+import "math"
+import "sync"
+
+// A HighPassFilter wraps an audio stream and attenuates the frequencies
+// below the configured cutoff. The filter is a 2nd-order Butterworth
+// biquad, so the roll-off is 12dB per octave.
+//
+// High-pass filters are typically used to remove rumble and other low
+// frequency noise from voice lines and recordings.
+type HighPassFilter struct {
+	mutex sync.Mutex
+	source io.Reader
+	sampleRate int
+	cutoff float64
+	filter biquadFilter
+}
+
+// Creates a new [HighPassFilter]. The cutoff frequency must be in the
+// (0, sampleRate/2) range. This method will panic otherwise.
+// If the sample rate is 0, [DefaultSampleRate] is used.
+func NewHighPassFilter(source io.Reader, cutoffHz float64, sampleRate int) *HighPassFilter {
+	sampleRate = resolveSampleRate(sampleRate, "NewHighPassFilter")
+	assertFilterFreqValidity(cutoffHz, sampleRate)
+	filter := &HighPassFilter {
+		source: source,
+		sampleRate: sampleRate,
+		cutoff: cutoffHz,
+	}
+	filter.filter.coeffs = newHighPassCoeffs(cutoffHz, sampleRate)
+	return filter
+}
+
+// Returns the underlying source.
+func (self *HighPassFilter) Source() io.Reader {
+	return self.source
+}
+
+// Returns the currently configured cutoff frequency.
+func (self *HighPassFilter) Cutoff() float64 {
+	self.mutex.Lock()
+	cutoff := self.cutoff
+	self.mutex.Unlock()
+	return cutoff
+}
+
+// Sets the cutoff frequency. The filter state is preserved, so the
+// change can be applied during playback. Like with the constructor,
+// the frequency must be in the (0, sampleRate/2) range.
+func (self *HighPassFilter) SetCutoff(cutoffHz float64) {
+	assertFilterFreqValidity(cutoffHz, self.sampleRate)
+	self.mutex.Lock()
+	self.cutoff = cutoffHz
+	self.filter.coeffs = newHighPassCoeffs(cutoffHz, self.sampleRate)
+	self.mutex.Unlock()
+}
+
+// Implements [io.Reader]. The returned read length will always be
+// multiple of 4, aligning to Ebitengine's sample size.
+func (self *HighPassFilter) Read(buffer []byte) (int, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	n, err := readFrames(self.source, buffer)
+	self.filter.Process(buffer[0 : n])
+	return n, err
+}
+
+// Implements [io.Seeker]. The filter state is reset after seeking.
+//
+// If the underlying source doesn't implement [io.Seeker], [ErrNotSeekable]
+// is returned.
+func (self *HighPassFilter) Seek(offset int64, whence int) (int64, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	position, err := seekSource(self.source, offset, whence)
+	self.filter.Reset()
+	return position, err
+}
+
+// A BandPassFilter wraps an audio stream and attenuates the frequencies
+// outside a band around the configured center frequency. The width of the
+// band is controlled by the Q factor: higher values make the band narrower.
+//
+// Band-pass filters can be used for radio or telephone-like voice effects.
+type BandPassFilter struct {
+	mutex sync.Mutex
+	source io.Reader
+	sampleRate int
+	center float64
+	q float64
+	filter biquadFilter
+}
+
+// Creates a new [BandPassFilter]. The center frequency must be in the
+// (0, sampleRate/2) range, and q must be strictly positive. This method
+// will panic otherwise. If the sample rate is 0, [DefaultSampleRate] is used.
+func NewBandPassFilter(source io.Reader, centerHz, q float64, sampleRate int) *BandPassFilter {
+	sampleRate = resolveSampleRate(sampleRate, "NewBandPassFilter")
+	assertFilterFreqValidity(centerHz, sampleRate)
+	assertFilterQValidity(q)
+	filter := &BandPassFilter {
+		source: source,
+		sampleRate: sampleRate,
+		center: centerHz,
+		q: q,
+	}
+	filter.filter.coeffs = newBandPassCoeffs(centerHz, q, sampleRate)
+	return filter
+}
+
+// Returns the underlying source.
+func (self *BandPassFilter) Source() io.Reader {
+	return self.source
+}
+
+// Returns the currently configured center frequency and Q factor.
+func (self *BandPassFilter) Params() (float64, float64) {
+	self.mutex.Lock()
+	center, q := self.center, self.q
+	self.mutex.Unlock()
+	return center, q
+}
+
+// Sets the center frequency and Q factor. The same restrictions
+// as in [NewBandPassFilter] apply.
+func (self *BandPassFilter) SetParams(centerHz, q float64) {
+	assertFilterFreqValidity(centerHz, self.sampleRate)
+	assertFilterQValidity(q)
+	self.mutex.Lock()
+	self.center, self.q = centerHz, q
+	self.filter.coeffs = newBandPassCoeffs(centerHz, q, self.sampleRate)
+	self.mutex.Unlock()
+}
+
+// Implements [io.Reader]. The returned read length will always be
+// multiple of 4, aligning to Ebitengine's sample size.
+func (self *BandPassFilter) Read(buffer []byte) (int, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	n, err := readFrames(self.source, buffer)
+	self.filter.Process(buffer[0 : n])
+	return n, err
+}
+
+// Implements [io.Seeker]. The filter state is reset after seeking.
+//
+// If the underlying source doesn't implement [io.Seeker], [ErrNotSeekable]
+// is returned.
+func (self *BandPassFilter) Seek(offset int64, whence int) (int64, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	position, err := seekSource(self.source, offset, whence)
+	self.filter.Reset()
+	return position, err
+}
+
+// A NotchFilter wraps an audio stream and removes a narrow band of
+// frequencies around the configured center frequency. The width of the
+// band is controlled by the Q factor: higher values make the notch narrower.
+//
+// Notch filters are typically used to remove hum (e.g. at 50Hz or 60Hz).
+type NotchFilter struct {
+	mutex sync.Mutex
+	source io.Reader
+	sampleRate int
+	center float64
+	q float64
+	filter biquadFilter
+}
+
+// Creates a new [NotchFilter]. The center frequency must be in the
+// (0, sampleRate/2) range, and q must be strictly positive. This method
+// will panic otherwise. If the sample rate is 0, [DefaultSampleRate] is used.
+func NewNotchFilter(source io.Reader, centerHz, q float64, sampleRate int) *NotchFilter {
+	sampleRate = resolveSampleRate(sampleRate, "NewNotchFilter")
+	assertFilterFreqValidity(centerHz, sampleRate)
+	assertFilterQValidity(q)
+	filter := &NotchFilter {
+		source: source,
+		sampleRate: sampleRate,
+		center: centerHz,
+		q: q,
+	}
+	filter.filter.coeffs = newNotchCoeffs(centerHz, q, sampleRate)
+	return filter
+}
+
+// Returns the underlying source.
+func (self *NotchFilter) Source() io.Reader {
+	return self.source
+}
+
+// Returns the currently configured center frequency and Q factor.
+func (self *NotchFilter) Params() (float64, float64) {
+	self.mutex.Lock()
+	center, q := self.center, self.q
+	self.mutex.Unlock()
+	return center, q
+}
+
+// Sets the center frequency and Q factor. The same restrictions
+// as in [NewNotchFilter] apply.
+func (self *NotchFilter) SetParams(centerHz, q float64) {
+	assertFilterFreqValidity(centerHz, self.sampleRate)
+	assertFilterQValidity(q)
+	self.mutex.Lock()
+	self.center, self.q = centerHz, q
+	self.filter.coeffs = newNotchCoeffs(centerHz, q, self.sampleRate)
+	self.mutex.Unlock()
+}
+
+// Implements [io.Reader]. The returned read length will always be
+// multiple of 4, aligning to Ebitengine's sample size.
+func (self *NotchFilter) Read(buffer []byte) (int, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	n, err := readFrames(self.source, buffer)
+	self.filter.Process(buffer[0 : n])
+	return n, err
+}
+
+// Implements [io.Seeker]. The filter state is reset after seeking.
+//
+// If the underlying source doesn't implement [io.Seeker], [ErrNotSeekable]
+// is returned.
+func (self *NotchFilter) Seek(offset int64, whence int) (int64, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	position, err := seekSource(self.source, offset, whence)
+	self.filter.Reset()
+	return position, err
+}
+
+// --- shared biquad helpers ---
+
+// Coefficient formulas follow Robert Bristow-Johnson's "Audio EQ Cookbook".
+// All coefficients are already normalized by a0.
+type biquadCoeffs struct {
+	b0, b1, b2 float64
+	a1, a2 float64
+}
+
+// Stereo biquad filter, with separate state for each channel.
+type biquadFilter struct {
+	coeffs biquadCoeffs
+	left  biquadState
+	right biquadState
+}
+
+type biquadState struct {
+	x1, x2 float64 // previous inputs
+	y1, y2 float64 // previous outputs
+}
+
+// Filters all the samples in the given buffer, in place. len(buffer)
+// is expected to be a multiple of 4.
+func (self *biquadFilter) Process(buffer []byte) {
+	for len(buffer) >= 4 {
+		left, right := GetSampleAsF64(buffer)
+		left  = self.left.Next(&self.coeffs, left)
+		right = self.right.Next(&self.coeffs, right)
+		StoreNormF64SampleAsL16(buffer, left, right)
+		buffer = buffer[4 : ]
+	}
+}
+
+func (self *biquadFilter) Reset() {
+	self.left  = biquadState{}
+	self.right = biquadState{}
+}
+
+func (self *biquadState) Next(c *biquadCoeffs, x float64) float64 {
+	y := c.b0*x + c.b1*self.x1 + c.b2*self.x2 - c.a1*self.y1 - c.a2*self.y2
+	self.x2, self.x1 = self.x1, x
+	self.y2, self.y1 = self.y1, y
+	return y
+}
+
+func newHighPassCoeffs(cutoff float64, sampleRate int) biquadCoeffs {
+	cosW0, alpha := biquadPrecompute(cutoff, math.Sqrt2/2, sampleRate)
+	a0 := 1.0 + alpha
+	return biquadCoeffs {
+		b0: (1.0 + cosW0)/(2.0*a0),
+		b1: -(1.0 + cosW0)/a0,
+		b2: (1.0 + cosW0)/(2.0*a0),
+		a1: -2.0*cosW0/a0,
+		a2: (1.0 - alpha)/a0,
+	}
+}
+
+// Constant 0dB peak gain variant.
+func newBandPassCoeffs(center float64, q float64, sampleRate int) biquadCoeffs {
+	cosW0, alpha := biquadPrecompute(center, q, sampleRate)
+	a0 := 1.0 + alpha
+	return biquadCoeffs {
+		b0: alpha/a0,
+		b1: 0.0,
+		b2: -alpha/a0,
+		a1: -2.0*cosW0/a0,
+		a2: (1.0 - alpha)/a0,
+	}
+}
+
+func newNotchCoeffs(center float64, q float64, sampleRate int) biquadCoeffs {
+	cosW0, alpha := biquadPrecompute(center, q, sampleRate)
+	a0 := 1.0 + alpha
+	return biquadCoeffs {
+		b0: 1.0/a0,
+		b1: -2.0*cosW0/a0,
+		b2: 1.0/a0,
+		a1: -2.0*cosW0/a0,
+		a2: (1.0 - alpha)/a0,
+	}
+}
+
+// All-pass, which shifts the phase around the center without changing the gain.
+func newAllPassCoeffs(center float64, q float64, sampleRate int) biquadCoeffs {
+	cosW0, alpha := biquadPrecompute(center, q, sampleRate)
+	a0 := 1.0 + alpha
+	return biquadCoeffs {
+		b0: (1.0 - alpha)/a0,
+		b1: -2.0*cosW0/a0,
+		b2: 1.0,
+		a1: -2.0*cosW0/a0,
+		a2: (1.0 - alpha)/a0,
+	}
+}
+
+// Peaking EQ, boosting or cutting the given gain (in dB) around the center.
+func newPeakingCoeffs(center float64, q float64, gainDb float64, sampleRate int) biquadCoeffs {
+	cosW0, alpha := biquadPrecompute(center, q, sampleRate)
+	a := math.Pow(10, gainDb/40.0)
+	a0 := 1.0 + alpha/a
+	return biquadCoeffs {
+		b0: (1.0 + alpha*a)/a0,
+		b1: -2.0*cosW0/a0,
+		b2: (1.0 - alpha*a)/a0,
+		a1: -2.0*cosW0/a0,
+		a2: (1.0 - alpha/a)/a0,
+	}
+}
+
+// Returns cos(w0) and alpha, which are used by all the cookbook formulas.
+func biquadPrecompute(freq float64, q float64, sampleRate int) (float64, float64) {
+	w0 := 2.0*math.Pi*freq/float64(sampleRate)
+	return math.Cos(w0), math.Sin(w0)/(2.0*q)
+}
+
+func assertFilterFreqValidity(freq float64, sampleRate int) {
+	if sampleRate <= 0 { panic("sampleRate must be strictly positive") }
+	if freq <= 0 { panic("filter frequency must be strictly positive") }
+	if freq >= float64(sampleRate)/2 { panic("filter frequency must be below the Nyquist frequency (sampleRate/2)") }
+}
+
+func assertFilterQValidity(q float64) {
+	if q <= 0 { panic("filter q must be strictly positive") }
+}
@@ -0,0 +1,134 @@
+package edau
+
+import "io"
+import "math"
+import "bytes"
+import "testing"
+
+const testSampleRate = 44100
+
+func TestBandPassFilter(t *testing.T) {
+	const center = 1000.0
+	for _, freq := range []float64{ 50, 200, 500, 1000, 2000, 5000, 15000 } {
+		filter := NewBandPassFilter(bytes.NewReader(testSineL16(freq, 0.5, testSampleRate)), center, 2.0, testSampleRate)
+		gain := testFilterGain(t, filter, 0.5)
+		if freq == center {
+			if gain < 0.95 {
+				t.Fatalf("BandPassFilter expected no attenuation at %.0fHz, got gain %f", freq, gain)
+			}
+		} else if freq <= center/4 || freq >= center*4 {
+			if gain > 0.2 {
+				t.Fatalf("BandPassFilter expected attenuation at %.0fHz, got gain %f", freq, gain)
+			}
+		} else if gain >= 0.95 {
+			t.Fatalf("BandPassFilter expected some attenuation at %.0fHz, got gain %f", freq, gain)
+		}
+	}
+}
+
+func TestNotchFilter(t *testing.T) {
+	const center = 1000.0
+	for _, freq := range []float64{ 50, 200, 500, 1000, 2000, 5000, 15000 } {
+		filter := NewNotchFilter(bytes.NewReader(testSineL16(freq, 0.5, testSampleRate)), center, 2.0, testSampleRate)
+		gain := testFilterGain(t, filter, 0.5)
+		if freq == center {
+			if gain > 0.01 {
+				t.Fatalf("NotchFilter expected full attenuation at %.0fHz, got gain %f", freq, gain)
+			}
+		} else if freq <= center/4 || freq >= center*4 {
+			if gain < 0.95 {
+				t.Fatalf("NotchFilter expected no attenuation at %.0fHz, got gain %f", freq, gain)
+			}
+		}
+	}
+}
+
+func TestHighPassFilter(t *testing.T) {
+	const cutoff = 1000.0
+	for _, freq := range []float64{ 50, 100, 5000, 15000 } {
+		filter := NewHighPassFilter(bytes.NewReader(testSineL16(freq, 0.5, testSampleRate)), cutoff, testSampleRate)
+		gain := testFilterGain(t, filter, 0.5)
+		if freq < cutoff && gain > 0.02 {
+			t.Fatalf("HighPassFilter expected attenuation at %.0fHz, got gain %f", freq, gain)
+		} else if freq > cutoff && gain < 0.95 {
+			t.Fatalf("HighPassFilter expected no attenuation at %.0fHz, got gain %f", freq, gain)
+		}
+	}
+}
+
+func TestEqualizer(t *testing.T) {
+	const center = 1000.0
+	for _, freq := range []float64{ 50, 1000, 15000 } {
+		equalizer := NewEqualizer(bytes.NewReader(testSineL16(freq, 0.25, testSampleRate)), testSampleRate)
+		band := equalizer.AddBand(center, -6.0, 2.0)
+		gain := testFilterGain(t, equalizer, 0.25)
+		expected := 1.0
+		if freq == center { expected = math.Pow(10, -6.0/20.0) }
+		if math.Abs(gain - expected) > 0.05 {
+			t.Fatalf("Equalizer expected gain %f at %.0fHz, got %f", expected, freq, gain)
+		}
+
+		// boost instead of cut
+		equalizer.Seek(0, io.SeekStart)
+		equalizer.SetBandGain(band, 6.0)
+		gain = testFilterGain(t, equalizer, 0.25)
+		if freq == center { expected = math.Pow(10, 6.0/20.0) }
+		if math.Abs(gain - expected) > 0.05 {
+			t.Fatalf("Equalizer expected gain %f at %.0fHz after boost, got %f", expected, freq, gain)
+		}
+	}
+}
+
+// --- helper functions ---
+
+// Returns one second of a stereo sine wave in L16 format.
+func testSineL16(freq float64, amplitude float64, sampleRate int) []byte {
+	buffer := make([]byte, sampleRate*4)
+	for i := 0; i < sampleRate; i++ {
+		value := amplitude*math.Sin(2*math.Pi*freq*float64(i)/float64(sampleRate))
+		StoreNormF64SampleAsL16(buffer[i*4 : ], value, value)
+	}
+	return buffer
+}
+
+// Reads the whole stream and returns the ratio between the output
+// peak (ignoring the first half, where transients may happen) and
+// the given input amplitude.
+func testFilterGain(t *testing.T, stream io.Reader, amplitude float64) float64 {
+	output, err := io.ReadAll(stream)
+	if err != nil { t.Fatal(err) }
+	peak := 0.0
+	for i := len(output)/2 ; i + 4 <= len(output); i += 4 {
+		left, _ := GetSampleAsF64(output[i : ])
+		peak = math.Max(peak, math.Abs(left))
+	}
+	return peak/amplitude
+}
+
+func TestPhaserSweep(t *testing.T) {
+	// a sine going through the phaser is attenuated when a notch passes
+	// over its frequency, so its level must vary along the LFO cycle
+	input := testSineL16(1000, 0.5, testSampleRate)
+	levelRange := func(depth float64) (float64, float64) {
+		phaser := NewPhaser(bytes.NewReader(input), 4, 1.0, depth, 0.5, testSampleRate)
+		output, err := io.ReadAll(phaser)
+		if err != nil { t.Fatal(err) }
+		minLevel, maxLevel := math.Inf(1), 0.0
+		const blockSize = 441*4 // 10ms
+		for i := blockSize; i + blockSize <= len(output); i += blockSize {
+			level, _ := MeasureRMS(output[i : i + blockSize])
+			minLevel = math.Min(minLevel, level)
+			maxLevel = math.Max(maxLevel, level)
+		}
+		return minLevel, maxLevel
+	}
+
+	minLevel, maxLevel := levelRange(1.0)
+	if minLevel > maxLevel*0.25 {
+		t.Fatalf("expected notches sweeping over the tone (level range %f - %f)", minLevel, maxLevel)
+	}
+	minLevel, maxLevel = levelRange(0.0)
+	if minLevel < maxLevel*0.95 {
+		t.Fatalf("expected a steady level without sweep (level range %f - %f)", minLevel, maxLevel)
+	}
+}
@@ -0,0 +1,161 @@
+package edau
+
+import "io"
+import "math"
+import "sync"
+
+const flangerBaseDelayMs = 1.0
+const flangerMaxDepthMs  = 10.0
+const flangerMaxFeedback = 0.95
+
+// A Flanger wraps an audio stream and mixes it with a delayed copy of
+// itself, with the delay swept by a low frequency oscillator (LFO). Compared
+// to a [Chorus], the delays are much shorter and part of the output is fed
+// back into the delay line, which creates a comb filter with pronounced
+// resonances that sweep up and down, resulting in the classic "jet" sound.
+// Like in [Vibrato], the fractional delays are interpolated with
+// [InterpHermite6Pt3Ord].
+type Flanger struct {
+	mutex sync.Mutex
+	source io.Reader
+	sampleRate int
+	depth float64 // in samples
+	feedback float64
+	mix float64
+	lfo *LFO
+	line fractionalDelay
+}
+
+// Creates a new [Flanger]. The rate must be in [0, sampleRate/2), the depth
+// (maximum delay variation, in milliseconds) in [0, 10] and the mix in [0, 1].
+// The feedback can be negative, and it's clamped to [-0.95, 0.95] to prevent
+// runaway resonances. Typical values are around 0.25Hz, 2ms, 0.7 feedback and
+// 0.5 mix. This method will panic if any of the values are invalid.
+// If the sample rate is 0, [DefaultSampleRate] is used.
+func NewFlanger(source io.Reader, rateHz, depthMs, feedback, mix float64, sampleRate int) *Flanger {
+	sampleRate = resolveSampleRate(sampleRate, "NewFlanger")
+	assertFlangerDepthValidity(depthMs)
+	assertMixValidity(mix)
+	maxDelay := msToFrames(flangerBaseDelayMs + flangerMaxDepthMs, sampleRate)
+	return &Flanger {
+		source: source,
+		sampleRate: sampleRate,
+		lfo: NewLFO(LFOSine, rateHz, sampleRate),
+		depth: msToFrames(depthMs, sampleRate),
+		feedback: clampFlangerFeedback(feedback),
+		mix: mix,
+		line: newFractionalDelay(int(math.Ceil(maxDelay)), InterpHermite6Pt3Ord, 6),
+	}
+}
+
+// Returns the underlying source.
+func (self *Flanger) Source() io.Reader {
+	return self.source
+}
+
+// Returns the currently configured LFO rate, in Hz.
+func (self *Flanger) Rate() float64 {
+	return self.lfo.Rate()
+}
+
+// Sets the LFO rate, in Hz. This method will panic if the value
+// is negative or above the Nyquist frequency.
+func (self *Flanger) SetRate(rateHz float64) {
+	self.lfo.SetRate(rateHz)
+}
+
+// Returns the currently configured depth, in milliseconds.
+func (self *Flanger) Depth() float64 {
+	self.mutex.Lock()
+	depth := (self.depth*1000.0)/float64(self.sampleRate)
+	self.mutex.Unlock()
+	return depth
+}
+
+// Sets the depth, in milliseconds. The value must be in [0, 10].
+// This method will panic if the value is invalid.
+func (self *Flanger) SetDepth(depthMs float64) {
+	assertFlangerDepthValidity(depthMs)
+	self.mutex.Lock()
+	self.depth = msToFrames(depthMs, self.sampleRate)
+	self.mutex.Unlock()
+}
+
+// Returns the currently configured feedback.
+func (self *Flanger) Feedback() float64 {
+	self.mutex.Lock()
+	feedback := self.feedback
+	self.mutex.Unlock()
+	return feedback
+}
+
+// Sets the feedback. Values are clamped to [-0.95, 0.95], as higher
+// feedbacks would make the resonances grow out of control.
+func (self *Flanger) SetFeedback(feedback float64) {
+	self.mutex.Lock()
+	self.feedback = clampFlangerFeedback(feedback)
+	self.mutex.Unlock()
+}
+
+// Returns the currently configured mix factor.
+func (self *Flanger) Mix() float64 {
+	self.mutex.Lock()
+	mix := self.mix
+	self.mutex.Unlock()
+	return mix
+}
+
+// Sets the mix factor, which must be in [0, 1]. 0 is fully dry and 1
+// fully wet. This method will panic if the value is invalid.
+func (self *Flanger) SetMix(mix float64) {
+	assertMixValidity(mix)
+	self.mutex.Lock()
+	self.mix = mix
+	self.mutex.Unlock()
+}
+
+// Implements [io.Reader]. The returned read length will always be
+// multiple of 4, aligning to Ebitengine's sample size.
+func (self *Flanger) Read(buffer []byte) (int, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	baseDelay := self.line.MinDelay() + msToFrames(flangerBaseDelayMs, self.sampleRate)
+	n, err := readFrames(self.source, buffer)
+	for i := 0; i < n; i += 4 {
+		// the delayed sample is read before pushing the current one,
+		// as the current one depends on it through the feedback
+		left, right := GetSampleAsF64(buffer[i : ])
+		delay := baseDelay + self.depth*(0.5 + 0.5*self.lfo.Next())
+		wetLeft, wetRight := self.line.Tap(delay)
+		self.line.Push(left + wetLeft*self.feedback, right + wetRight*self.feedback)
+
+		left  = left*(1.0 - self.mix)  + wetLeft*self.mix
+		right = right*(1.0 - self.mix) + wetRight*self.mix
+		StoreNormF64SampleAsL16(buffer[i : ], left, right)
+	}
+	return n, err
+}
+
+// Implements [io.Seeker]. The delay line is cleared after seeking,
+// but the LFO phase is not reset.
+//
+// If the underlying source doesn't implement [io.Seeker], [ErrNotSeekable]
+// is returned.
+func (self *Flanger) Seek(offset int64, whence int) (int64, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	position, err := seekSource(self.source, offset, whence)
+	self.line.Reset()
+	return position, err
+}
+
+func assertFlangerDepthValidity(depthMs float64) {
+	if depthMs < 0 || depthMs > flangerMaxDepthMs { panic("flanger depth must be in [0, 10] milliseconds") }
+}
+
+func clampFlangerFeedback(feedback float64) float64 {
+	if feedback >  flangerMaxFeedback { return  flangerMaxFeedback }
+	if feedback < -flangerMaxFeedback { return -flangerMaxFeedback }
+	return feedback
+}
@@ -0,0 +1,163 @@
+package edau
+
+import "io"
+import "math"
+import "math/bits"
+import "math/rand"
+import "time"
+
+// Creates a source of silence with the given duration. The source
+// implements [io.Reader] and returns [io.EOF] once exhausted.
+// If the sample rate is 0, [DefaultSampleRate] is used.
+func NewSilence(duration time.Duration, sampleRate int) io.Reader {
+	sampleRate = resolveSampleRate(sampleRate, "NewSilence")
+	if duration < 0 { panic("NewSilence duration can't be negative") }
+	return &silenceSource{ remaining: BytesForDuration(duration, sampleRate) }
+}
+
+// Creates a source that generates a sine wave with the given frequency,
+// amplitude and duration. The amplitude must be in [0, 1]. The source
+// implements [io.Reader] and returns [io.EOF] once exhausted.
+// If the sample rate is 0, [DefaultSampleRate] is used.
+func NewTone(freqHz, amplitude float64, duration time.Duration, sampleRate int) io.Reader {
+	sampleRate = resolveSampleRate(sampleRate, "NewTone")
+	if duration < 0 { panic("NewTone duration can't be negative") }
+	if freqHz < 0 || freqHz >= float64(sampleRate)/2 {
+		panic("NewTone frequency must be in [0, sampleRate/2)")
+	}
+	assertUnitRangeParam("amplitude", amplitude)
+	return &toneSource {
+		phaseStep: freqHz/float64(sampleRate),
+		amplitude: amplitude,
+		remaining: BytesForDuration(duration, sampleRate),
+	}
+}
+
+// Creates an infinite source of white noise with the given amplitude, which
+// must be in [0, 1]. The left and right channels are independent. The seed
+// is used to initialize the random number generator, so the same seed always
+// produces the same noise.
+func NewWhiteNoise(amplitude float64, seed int64) io.Reader {
+	assertUnitRangeParam("amplitude", amplitude)
+	return &whiteNoiseSource {
+		rng: rand.New(rand.NewSource(seed)),
+		amplitude: amplitude,
+	}
+}
+
+// Creates an infinite source of pink noise with the given amplitude, which
+// must be in [0, 1]. Pink noise has equal energy per octave, so it sounds
+// more natural than white noise and it's commonly used for calibration.
+// The noise is generated with the Voss-McCartney algorithm. The left and
+// right channels are independent, and the seed is used to initialize the
+// random number generator, like in [NewWhiteNoise].
+func NewPinkNoise(amplitude float64, seed int64) io.Reader {
+	assertUnitRangeParam("amplitude", amplitude)
+	noise := &pinkNoiseSource {
+		rng: rand.New(rand.NewSource(seed)),
+		amplitude: amplitude,
+	}
+	for i := 0; i < pinkNoiseRows; i++ {
+		noise.leftRows[i]  = noise.rng.Float64()*2.0 - 1.0
+		noise.rightRows[i] = noise.rng.Float64()*2.0 - 1.0
+		noise.leftSum  += noise.leftRows[i]
+		noise.rightSum += noise.rightRows[i]
+	}
+	return noise
+}
+
+type silenceSource struct {
+	remaining int64 // in bytes
+}
+
+func (self *silenceSource) Read(buffer []byte) (int, error) {
+	if self.remaining == 0 { return 0, io.EOF }
+	n := readSilence(buffer, self.remaining)
+	self.remaining -= int64(n)
+	return n, nil
+}
+
+// Fills the given buffer with whole silent samples, up to the given
+// number of remaining bytes. Returns the number of bytes written.
+func readSilence(buffer []byte, remaining int64) int {
+	buffer = buffer[0 : len(buffer) - (len(buffer) & 0b11)]
+	if int64(len(buffer)) > remaining {
+		buffer = buffer[0 : remaining]
+	}
+	for i := range buffer { buffer[i] = 0 }
+	return len(buffer)
+}
+
+type toneSource struct {
+	phase float64 // in [0, 1)
+	phaseStep float64
+	amplitude float64
+	remaining int64 // in bytes
+}
+
+func (self *toneSource) Read(buffer []byte) (int, error) {
+	if self.remaining == 0 { return 0, io.EOF }
+	buffer = buffer[0 : len(buffer) - (len(buffer) & 0b11)]
+	if int64(len(buffer)) > self.remaining {
+		buffer = buffer[0 : self.remaining]
+	}
+	for i := 0; i < len(buffer); i += 4 {
+		value := self.amplitude*math.Sin(2.0*math.Pi*self.phase)
+		StoreNormF64SampleAsL16(buffer[i : ], value, value)
+		self.phase += self.phaseStep
+		if self.phase >= 1.0 { self.phase -= 1.0 }
+	}
+	self.remaining -= int64(len(buffer))
+	return len(buffer), nil
+}
+
+type whiteNoiseSource struct {
+	rng *rand.Rand
+	amplitude float64
+}
+
+func (self *whiteNoiseSource) Read(buffer []byte) (int, error) {
+	buffer = buffer[0 : len(buffer) - (len(buffer) & 0b11)]
+	for i := 0; i < len(buffer); i += 4 {
+		left  := self.amplitude*(self.rng.Float64()*2.0 - 1.0)
+		right := self.amplitude*(self.rng.Float64()*2.0 - 1.0)
+		StoreNormF64SampleAsL16(buffer[i : ], left, right)
+	}
+	return len(buffer), nil
+}
+
+const pinkNoiseRows = 16
+
+type pinkNoiseSource struct {
+	rng *rand.Rand
+	amplitude float64
+	counter uint32
+	leftRows  [pinkNoiseRows]float64
+	rightRows [pinkNoiseRows]float64
+	leftSum  float64
+	rightSum float64
+}
+
+func (self *pinkNoiseSource) Read(buffer []byte) (int, error) {
+	buffer = buffer[0 : len(buffer) - (len(buffer) & 0b11)]
+	const normalization = 1.0/(pinkNoiseRows + 1)
+	for i := 0; i < len(buffer); i += 4 {
+		// update one row, selected by the number of trailing zeros of the
+		// counter, so row k is updated every 2^(k + 1) samples
+		self.counter += 1
+		row := bits.TrailingZeros32(self.counter)
+		if row < pinkNoiseRows {
+			newLeft  := self.rng.Float64()*2.0 - 1.0
+			newRight := self.rng.Float64()*2.0 - 1.0
+			self.leftSum  += newLeft  - self.leftRows[row]
+			self.rightSum += newRight - self.rightRows[row]
+			self.leftRows[row], self.rightRows[row] = newLeft, newRight
+		}
+
+		// add some white noise on top and store
+		left  := self.leftSum  + self.rng.Float64()*2.0 - 1.0
+		right := self.rightSum + self.rng.Float64()*2.0 - 1.0
+		StoreNormF64SampleAsL16(buffer[i : ], self.amplitude*left*normalization, self.amplitude*right*normalization)
+	}
+	return len(buffer), nil
+}
@@ -2,10 +2,11 @@ module github.com/tinne26/edau
 
 go 1.19
 
+require github.com/hajimehoshi/ebiten/v2 v2.3.7
+
 require (
 	github.com/go-gl/glfw/v3.3/glfw v0.0.0-20220320163800-277f93cfa958 // indirect
 	github.com/gofrs/flock v0.8.1 // indirect
-	github.com/hajimehoshi/ebiten/v2 v2.3.7 // indirect
 	github.com/hajimehoshi/go-mp3 v0.3.3 // indirect
 	github.com/hajimehoshi/oto/v2 v2.1.0 // indirect
 	github.com/jezek/xgb v1.0.0 // indirect
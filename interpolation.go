@@ -1,5 +1,7 @@
 package edau
 
+import "math"
+
 // An interpolator function receives a slice of values, the position at which
 // we want to interpolate, and returns the interpolated value.
 //
@@ -94,6 +96,22 @@ func InterpHermite4Pt3Ord(samples []float64, x float64) float64 {
 	return ((c3*x1 + c2)*x1 + c1)*x1 + c0
 }
 
+// Like [InterpHermite4Pt3Ord], but also returns the derivative of the interpolating
+// polynomial at x, in units per sample. Useful to find peaks or zero crossings
+// between samples, or to estimate the instantaneous frequency.
+//
+// len(samples) must be at least 4.
+func InterpHermite4Pt3OrdD(samples []float64, x float64) (float64, float64) {
+	c0 := samples[1]
+	c1 := 0.5*(samples[2] - samples[0])
+	c2 := samples[0] - 2.5*samples[1] + 2.0*samples[2] - 0.5*samples[3]
+	c3 := 0.5*(samples[3] - samples[0]) + 1.5*(samples[1] - samples[2])
+	x1 := x - 1.0
+	value := ((c3*x1 + c2)*x1 + c1)*x1 + c0
+	derivative := (3.0*c3*x1 + 2.0*c2)*x1 + c1
+	return value, derivative
+}
+
 // 6-point, 3rd-order Hermite interpolation. Samples are considered to start at zero.
 // x is the position at which we want to interpolate. For best results, x should be
 // between 2.0 and 3.0.
@@ -126,3 +144,62 @@ func InterpHermite6Pt3Ord(samples []float64, x float64) float64 {
 // 	x2 := x - 2.0
 // 	return ((((c5*x2 + c4)*x2 + c3)*x2 + c2)*x2 + c1)*x2 + c0
 // }
+
+// Interpolates all the given channels at the same position x with the given
+// interpolator, and returns the results in a new slice, one value per channel.
+// This is mainly useful for planar (deinterleaved) multichannel audio, where
+// each channel has its own slice of samples. All the channels must meet the
+// requirements of the interpolator.
+func InterpolateFrame(channels [][]float64, x float64, interp InterpolatorFunc) []float64 {
+	values := make([]float64, len(channels))
+	for i, samples := range channels {
+		values[i] = interp(samples, x)
+	}
+	return values
+}
+
+// Measures the quality of an interpolator by resampling a known sine and
+// comparing the results with the exact values. The result is the signal to
+// noise ratio in dB, where higher is better. A perfect interpolation returns
+// +Inf. This can be used to compare interpolators, or to check how well an
+// interpolator deals with the frequencies that are relevant for your content.
+//
+// The freqRatio is the frequency of the sine relative to the sample rate.
+// For example, a 441Hz tone at 44100Hz would be 0.01. Interpolation quality
+// degrades quickly as the ratio approaches the Nyquist limit (0.5). The
+// window is the number of samples passed to the interpolator, as in
+// [NewSpeedShifter], with the interpolation positions falling between the
+// two central samples.
+//
+// The window must be an even number >= 2, and freqRatio must be in (0, 0.5).
+// This method will panic otherwise.
+func InterpolationSNR(interp InterpolatorFunc, window int, freqRatio float64) float64 {
+	if window < 2 || window % 2 != 0 { panic("InterpolationSNR window must be an even number >= 2") }
+	if freqRatio <= 0 || freqRatio >= 0.5 { panic("InterpolationSNR freqRatio must be in (0, 0.5)") }
+
+	// the fractional positions follow the golden ratio sequence, which
+	// covers the [0, 1) range evenly without depending on freqRatio
+	const numPositions = 4096
+	const goldenFrac = 0.6180339887498949
+	angularFreq := 2*math.Pi*freqRatio
+	center := window/2 - 1
+	samples := make([]float64, window)
+	var signalPower, noisePower float64
+	for i := 0; i < numPositions; i++ {
+		// fill the window around the current base sample
+		base := i*7
+		for k := 0; k < window; k++ {
+			samples[k] = math.Sin(angularFreq*float64(base + k - center))
+		}
+
+		// interpolate and compare with the exact value
+		frac := math.Mod(float64(i)*goldenFrac, 1.0)
+		expected := math.Sin(angularFreq*(float64(base) + frac))
+		diff := interp(samples, float64(center) + frac) - expected
+		signalPower += expected*expected
+		noisePower  += diff*diff
+	}
+
+	if noisePower == 0 { return math.Inf(1) }
+	return 10*math.Log10(signalPower/noisePower)
+}
@@ -81,6 +81,35 @@ func TestInterpHermite4pt3Ord(t *testing.T) {
 	}
 }
 
+func TestInterpHermite4Pt3OrdD(t *testing.T) {
+	const h = 1e-6
+	for _, loc := range testLocations {
+		samples, target := alignSamplesAndTarget4(testPoints, loc)
+		value, derivative := InterpHermite4Pt3OrdD(samples, target)
+		expectValue := InterpHermite4Pt3Ord(samples, target)
+		if value != expectValue {
+			t.Fatalf("TestInterpHermite4Pt3OrdD for %f expected value %f but got %f", loc, expectValue, value)
+		}
+		numeric := (InterpHermite4Pt3Ord(samples, target + h) - InterpHermite4Pt3Ord(samples, target - h))/(2*h)
+		if math.Abs(derivative - numeric) > 1e-6 {
+			t.Fatalf("TestInterpHermite4Pt3OrdD for %f expected derivative %f but got %f", loc, numeric, derivative)
+		}
+	}
+
+	// the slope of a sine with 16 samples per period should be close to the
+	// analytical derivative (with 4 samples per period it's way too rough)
+	const w = 2*math.Pi/16
+	samples := []float64{ 0, math.Sin(w), math.Sin(2*w), math.Sin(3*w) }
+	for _, loc := range testLocations {
+		x := loc - 6.0 // in [1, 2)
+		_, derivative := InterpHermite4Pt3OrdD(samples, x)
+		expect := w*math.Cos(w*x)
+		if math.Abs(derivative - expect) > 0.05*w {
+			t.Fatalf("TestInterpHermite4Pt3OrdD for %f expected slope %f but got %f", x, expect, derivative)
+		}
+	}
+}
+
 func TestInterpFastHermite6Pt3Ord(t *testing.T) {
 	for _, loc := range testLocations {
 		samples, target := alignSamplesAndTarget6(testPoints, loc)
@@ -93,6 +122,50 @@ func TestInterpFastHermite6Pt3Ord(t *testing.T) {
 	}
 }
 
+func TestInterpolateFrame(t *testing.T) {
+	channels := [][]float64{
+		{ 0.0, 0.5, 1.0, 0.5, 0.0, -0.5 },
+		{ -0.5, -0.3, 0.1, 0.2, 0.4, 0.1 },
+		{ 1.0, 1.0, 1.0, 1.0, 1.0, 1.0 },
+	}
+	values := InterpolateFrame(channels, 2.3, InterpHermite6Pt3Ord)
+	if len(values) != len(channels) { t.Fatalf("expected %d values, got %d", len(channels), len(values)) }
+	for i, samples := range channels {
+		expected := InterpHermite6Pt3Ord(samples, 2.3)
+		if values[i] != expected { t.Fatalf("channel %d: expected %f, got %f", i, expected, values[i]) }
+	}
+	if len(InterpolateFrame(nil, 2.3, InterpHermite6Pt3Ord)) != 0 {
+		t.Fatal("expected no values for no channels")
+	}
+}
+
+func TestInterpolationSNR(t *testing.T) {
+	// quality must degrade as frequencies approach the nyquist limit
+	prevSNR := math.Inf(1)
+	for _, ratio := range []float64{0.01, 0.05, 0.1, 0.25, 0.45} {
+		snr := InterpolationSNR(InterpHermite6Pt3Ord, 6, ratio)
+		if snr >= prevSNR {
+			t.Fatalf("TestInterpolationSNR expected SNR to decrease at ratio %f (%f >= %f)", ratio, snr, prevSNR)
+		}
+		prevSNR = snr
+	}
+
+	// bigger windows must give better results at moderate frequencies
+	if snr := InterpolationSNR(InterpHermite6Pt3Ord, 6, 0.05); snr < 80 {
+		t.Fatalf("TestInterpolationSNR expected Hermite6 SNR > 80dB, got %f", snr)
+	}
+	hermite4 := InterpolationSNR(InterpHermite4Pt3Ord, 4, 0.1)
+	hermite6 := InterpolationSNR(InterpHermite6Pt3Ord, 6, 0.1)
+	if hermite4 >= hermite6 {
+		t.Fatalf("TestInterpolationSNR expected Hermite6 (%f) to beat Hermite4 (%f)", hermite6, hermite4)
+	}
+	lagrange6 := InterpolationSNR(InterpLagrange6Pt5Ord, 6, 0.1)
+	lagrange8 := InterpolationSNR(InterpLagrangeN, 8, 0.1)
+	if lagrange6 >= lagrange8 {
+		t.Fatalf("TestInterpolationSNR expected LagrangeN (8) (%f) to beat Lagrange6 (%f)", lagrange8, lagrange6)
+	}
+}
+
 // benchmarks
 
 func BenchmarkLagrangeN4(b *testing.B) {
@@ -0,0 +1,128 @@
+package edau
+
+import "math"
+import "sync/atomic"
+
+// Waveform shapes for an [LFO].
+type LFOShape uint32
+
+const (
+	LFOSine LFOShape = iota
+	LFOTriangle
+	LFOSquare
+	LFOSaw
+)
+
+// An LFO (low frequency oscillator) generates a periodic control signal
+// in [-1, 1], one value per sample, for modulation effects like tremolo,
+// vibrato or auto-pan.
+//
+// All the shapes start at their minimum (-1) at phase 0 and reach their
+// maximum (1) at phase 0.5, except for the saw, which rises linearly
+// through the whole period. Starting at the minimum makes the typical
+// unipolar modulation 0.5 + 0.5*value start from zero, which means that
+// effects begin unmodulated and don't click at the start of the playback.
+//
+// The rate and shape can be changed at any time from any goroutine, and
+// the phase stays continuous across the changes. The methods that access
+// the phase (Next, At, Advance, Phase and SetPhase) must be called from a
+// single goroutine, typically the audio one, or be otherwise synchronized.
+type LFO struct {
+	rateBits uint64 // float64 bits, accessed atomically (first for 64-bit alignment)
+	shape uint32 // accessed atomically
+	sampleRate int
+	phase float64 // in [0, 1)
+}
+
+// Creates a new [LFO]. The rate must be in [0, sampleRate/2). This method
+// will panic otherwise, or if the shape is invalid. If the sample rate is 0,
+// [DefaultSampleRate] is used.
+func NewLFO(shape LFOShape, rateHz float64, sampleRate int) *LFO {
+	sampleRate = resolveSampleRate(sampleRate, "NewLFO")
+	assertLFOShapeValidity(shape)
+	assertLFORateValidity(rateHz, sampleRate)
+	return &LFO {
+		shape: uint32(shape),
+		rateBits: math.Float64bits(rateHz),
+		sampleRate: sampleRate,
+	}
+}
+
+// Returns the currently configured rate, in Hz.
+func (self *LFO) Rate() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&self.rateBits))
+}
+
+// Sets the rate, in Hz. The change applies from the next sample, without
+// resetting the phase. This method will panic if the value is negative or
+// above the Nyquist frequency.
+func (self *LFO) SetRate(rateHz float64) {
+	assertLFORateValidity(rateHz, self.sampleRate)
+	atomic.StoreUint64(&self.rateBits, math.Float64bits(rateHz))
+}
+
+// Returns the currently configured shape.
+func (self *LFO) Shape() LFOShape {
+	return LFOShape(atomic.LoadUint32(&self.shape))
+}
+
+// Sets the shape. The phase is preserved, but notice that switching
+// shapes can still cause a jump in the output value. This method will
+// panic if the shape is invalid.
+func (self *LFO) SetShape(shape LFOShape) {
+	assertLFOShapeValidity(shape)
+	atomic.StoreUint32(&self.shape, uint32(shape))
+}
+
+// Returns the current phase, in [0, 1).
+func (self *LFO) Phase() float64 {
+	return self.phase
+}
+
+// Sets the current phase. Values outside [0, 1) are wrapped.
+func (self *LFO) SetPhase(phase float64) {
+	self.phase = phase - math.Floor(phase)
+}
+
+// Returns the value at the current phase and advances one sample.
+func (self *LFO) Next() float64 {
+	value := self.At(0)
+	self.Advance()
+	return value
+}
+
+// Returns the value at the current phase plus the given offset, without
+// advancing. Useful to derive multiple modulation signals from the same
+// oscillator, like the voices of a chorus or the channels of an auto-pan.
+func (self *LFO) At(phaseOffset float64) float64 {
+	phase := self.phase + phaseOffset
+	phase -= math.Floor(phase)
+	switch LFOShape(atomic.LoadUint32(&self.shape)) {
+	case LFOSine:
+		return -math.Cos(2.0*math.Pi*phase)
+	case LFOTriangle:
+		return 1.0 - 4.0*math.Abs(phase - 0.5)
+	case LFOSquare:
+		if phase < 0.5 { return -1.0 }
+		return 1.0
+	case LFOSaw:
+		return 2.0*phase - 1.0
+	default:
+		panic("invalid LFO shape")
+	}
+}
+
+// Advances the phase one sample without computing any value.
+func (self *LFO) Advance() {
+	self.phase += self.Rate()/float64(self.sampleRate)
+	if self.phase >= 1.0 { self.phase -= 1.0 }
+}
+
+func assertLFOShapeValidity(shape LFOShape) {
+	if shape > LFOSaw { panic("invalid LFO shape") }
+}
+
+func assertLFORateValidity(rateHz float64, sampleRate int) {
+	if rateHz < 0 { panic("LFO rate can't be negative") }
+	if rateHz >= float64(sampleRate)/2 { panic("LFO rate must be below the Nyquist frequency (sampleRate/2)") }
+}
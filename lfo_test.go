@@ -0,0 +1,44 @@
+package edau
+
+import "math"
+import "testing"
+
+func TestLFO(t *testing.T) {
+	const eps = 0.000001
+	lfo := NewLFO(LFOSine, 1000, 8000) // 8 samples per period
+	expected := map[LFOShape][]float64 {
+		LFOSine: { -1, -math.Sqrt2/2, 0, math.Sqrt2/2, 1, math.Sqrt2/2, 0, -math.Sqrt2/2 },
+		LFOTriangle: { -1, -0.5, 0, 0.5, 1, 0.5, 0, -0.5 },
+		LFOSquare: { -1, -1, -1, -1, 1, 1, 1, 1 },
+		LFOSaw: { -1, -0.75, -0.5, -0.25, 0, 0.25, 0.5, 0.75 },
+	}
+	for shape, values := range expected {
+		lfo.SetShape(shape)
+		lfo.SetPhase(0)
+		for i, value := range values {
+			if math.Abs(lfo.At(0.5) - lfo.At(-0.5)) > eps {
+				t.Fatalf("shape %d: offsets of a full period must match", shape)
+			}
+			got := lfo.Next()
+			if math.Abs(got - value) > eps {
+				t.Fatalf("shape %d, sample %d: expected %f, got %f", shape, i, value, got)
+			}
+		}
+		if lfo.Phase() > eps && lfo.Phase() < 1 - eps {
+			t.Fatalf("shape %d: expected phase back at 0, got %f", shape, lfo.Phase())
+		}
+	}
+
+	// rate changes must keep the phase continuous
+	lfo.SetShape(LFOSaw)
+	lfo.SetPhase(0)
+	lfo.Next()
+	lfo.Next()
+	lfo.SetRate(500)
+	if got := lfo.Next(); math.Abs(got - (-0.5)) > eps {
+		t.Fatalf("expected -0.5 after rate change, got %f", got)
+	}
+	if got := lfo.Next(); math.Abs(got - (-0.375)) > eps {
+		t.Fatalf("expected -0.375 after rate change, got %f", got)
+	}
+}
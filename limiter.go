@@ -0,0 +1,228 @@
+package edau
+
+import "io"
+import "math"
+import "sync"
+import "time"
+
+const limiterReleaseTime = 0.05 // in seconds
+
+// A Limiter wraps an audio stream and applies gain reduction to keep all
+// the samples below a given ceiling. The limiter looks ahead in the stream
+// in order to start reducing the gain smoothly before the peaks arrive, so
+// the ceiling is never exceeded and there's no distortion.
+//
+// The looking ahead requires delaying the audio, so the limiter adds a latency
+// equal to the lookahead duration: the first samples served will be silence,
+// and once the underlying source reaches EOF, the limiter will still serve the
+// remaining lookahead samples before returning EOF too. Typical lookahead values
+// are around 5 milliseconds.
+type Limiter struct {
+	mutex sync.Mutex
+	source io.Reader
+	ceiling float64
+	gain float64
+	releaseCoef float64
+	line delayLine
+	minFilter slidingMin
+	avgFilter movingAverage
+	pendingFlush int // samples left to serve after the source's EOF, or -1
+}
+
+// Creates a new [Limiter]. The ceiling must be in (0, 1], and the lookahead must
+// be at least one sample long. This method will panic otherwise.
+// If the sample rate is 0, [DefaultSampleRate] is used.
+func NewLimiter(source io.Reader, ceiling float64, lookahead time.Duration, sampleRate int) *Limiter {
+	sampleRate = resolveSampleRate(sampleRate, "NewLimiter")
+	frames := int(SamplesForDuration(lookahead, sampleRate))
+	if frames < 1 { panic("NewLimiter lookahead must be at least one sample long") }
+	assertCeilingValidity(ceiling)
+
+	limiter := &Limiter {
+		source: source,
+		ceiling: ceiling,
+		releaseCoef: 1.0 - math.Exp(-1.0/(limiterReleaseTime*float64(sampleRate))),
+		line: newDelayLine(frames),
+		minFilter: newSlidingMin(frames + 1),
+		avgFilter: newMovingAverage(frames),
+	}
+	limiter.internalReset()
+	return limiter
+}
+
+// Returns the underlying source.
+func (self *Limiter) Source() io.Reader {
+	return self.source
+}
+
+// Returns the currently configured ceiling.
+func (self *Limiter) Ceiling() float64 {
+	self.mutex.Lock()
+	ceiling := self.ceiling
+	self.mutex.Unlock()
+	return ceiling
+}
+
+// Sets the ceiling, which must be in (0, 1]. This method will panic if the
+// value is invalid. The new ceiling is only guaranteed to be respected after
+// the lookahead duration has elapsed.
+func (self *Limiter) SetCeiling(ceiling float64) {
+	assertCeilingValidity(ceiling)
+	self.mutex.Lock()
+	self.ceiling = ceiling
+	self.mutex.Unlock()
+}
+
+// Implements [io.Reader]. The returned read length will always be
+// multiple of 4, aligning to Ebitengine's sample size.
+func (self *Limiter) Read(buffer []byte) (int, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	buffer = buffer[0 : len(buffer) - (len(buffer) & 0b11)]
+
+	// regular processing
+	var n int
+	var err error
+	if self.pendingFlush < 0 {
+		n, err = readFrames(self.source, buffer)
+		for i := 0; i < n; i += 4 {
+			left, right := GetSampleAsF64(buffer[i : ])
+			self.processSample(buffer[i : ], left, right)
+		}
+		if err != io.EOF { return n, err }
+		self.pendingFlush = len(self.line.buffer)/4
+	}
+
+	// flush lookahead samples after EOF
+	for n < len(buffer) && self.pendingFlush > 0 {
+		self.processSample(buffer[n : ], 0, 0)
+		self.pendingFlush -= 1
+		n += 4
+	}
+	if self.pendingFlush == 0 { return n, io.EOF }
+	return n, nil
+}
+
+// Implements [io.Seeker]. The lookahead buffer is cleared after seeking.
+//
+// If the underlying source doesn't implement [io.Seeker], [ErrNotSeekable]
+// is returned.
+func (self *Limiter) Seek(offset int64, whence int) (int64, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	position, err := seekSource(self.source, offset, whence)
+	self.internalReset()
+	return position, err
+}
+
+// Pushes the given sample into the lookahead buffer and stores
+// the delayed sample with the gain reduction applied.
+func (self *Limiter) processSample(buffer []byte, left, right float64) {
+	// compute the gain required for the incoming sample
+	required := 1.0
+	peak := math.Max(math.Abs(left), math.Abs(right))
+	if peak > self.ceiling { required = self.ceiling/peak }
+
+	// The min filter ensures that the gain is low enough for all the samples
+	// within the lookahead, and the moving average smooths the transitions
+	// without going back above the required gains.
+	target := self.avgFilter.Push(self.minFilter.Push(required))
+	if target < self.gain {
+		self.gain = target
+	} else {
+		self.gain += (target - self.gain)*self.releaseCoef
+	}
+
+	outLeft, outRight := self.line.Peek()
+	self.line.Push(left, right)
+	StoreNormF64SampleAsL16(buffer, outLeft*self.gain, outRight*self.gain)
+}
+
+func (self *Limiter) internalReset() {
+	self.gain = 1.0
+	self.pendingFlush = -1
+	self.line.Reset()
+	self.minFilter.Reset()
+	self.avgFilter.Reset(1.0)
+}
+
+func assertCeilingValidity(ceiling float64) {
+	if ceiling <= 0 || ceiling > 1.0 { panic("ceiling must be in (0, 1]") }
+}
+
+// --- helper filters for the limiter ---
+
+// Keeps track of the minimum value among the last n pushed values.
+// Amortized O(1) through a monotonic deque.
+type slidingMin struct {
+	window int64
+	count int64
+	values []float64
+	indices []int64
+	head int
+	size int
+}
+
+func newSlidingMin(window int) slidingMin {
+	return slidingMin {
+		window: int64(window),
+		values: make([]float64, window + 1),
+		indices: make([]int64, window + 1),
+	}
+}
+
+// Pushes a new value and returns the minimum within the window.
+func (self *slidingMin) Push(value float64) float64 {
+	// discard values that can't be the minimum anymore
+	for self.size > 0 {
+		back := (self.head + self.size - 1) % len(self.values)
+		if self.values[back] < value { break }
+		self.size -= 1
+	}
+
+	// append new value and discard expired front
+	back := (self.head + self.size) % len(self.values)
+	self.values[back], self.indices[back] = value, self.count
+	self.size += 1
+	if self.indices[self.head] <= self.count - self.window {
+		self.head = (self.head + 1) % len(self.values)
+		self.size -= 1
+	}
+	self.count += 1
+	return self.values[self.head]
+}
+
+func (self *slidingMin) Reset() {
+	self.count, self.head, self.size = 0, 0, 0
+}
+
+// Average of the last n pushed values.
+type movingAverage struct {
+	values []float64
+	index int
+	sum float64
+}
+
+func newMovingAverage(window int) movingAverage {
+	return movingAverage{ values: make([]float64, window) }
+}
+
+// Pushes a new value and returns the average within the window.
+func (self *movingAverage) Push(value float64) float64 {
+	self.sum += value - self.values[self.index]
+	self.values[self.index] = value
+	self.index += 1
+	if self.index == len(self.values) {
+		// recompute the sum periodically to prevent error accumulation
+		self.index, self.sum = 0, 0
+		for _, value := range self.values { self.sum += value }
+	}
+	return self.sum/float64(len(self.values))
+}
+
+// Fills the window with the given value.
+func (self *movingAverage) Reset(value float64) {
+	for i := range self.values { self.values[i] = value }
+	self.sum = value*float64(len(self.values))
+	self.index = 0
+}
@@ -0,0 +1,215 @@
+package edau
+
+import "io"
+import "math"
+import "errors"
+
+import "github.com/hajimehoshi/ebiten/v2/audio"
+
+const loopFinderBlockFrames = 1024
+const loopFinderRefineFrames = 2048
+
+// Returned by [SnapToZeroCrossing] when no zero crossing
+// is found within the search window.
+var ErrNoZeroCrossing = errors.New("no zero crossing found within the search window")
+
+// Scans the stream around the given approximate byte position and returns
+// the position of the nearest zero crossing, aligned to 4 bytes. Loop points
+// placed at zero crossings avoid the clicks caused by abrupt jumps in the
+// signal. Both channels are combined (summed) to detect the crossings, and
+// the returned position is the frame closest to zero at each crossing.
+//
+// The searchWindow is given in bytes and applies to both sides of approxByte.
+// If no zero crossing is found, approxByte aligned down to 4 bytes is returned
+// along with [ErrNoZeroCrossing].
+//
+// The stream's playback position is restored before returning.
+func SnapToZeroCrossing(stream StdAudioStream, approxByte int64, searchWindow int64) (int64, error) {
+	if searchWindow < 0 { panic("SnapToZeroCrossing searchWindow can't be negative") }
+	approxByte -= approxByte & 0b11
+	start, end := approxByte - searchWindow, approxByte + searchWindow + 4
+	start -= start & 0b11
+	if start < 0 { start = 0 }
+	if length := stream.Length(); end > length { end = length - (length & 0b11) }
+	if end - start < 8 { return approxByte, ErrNoZeroCrossing }
+
+	// read the search region, restoring the position afterwards
+	data, err := readStreamRegion(stream, start, end)
+	if err != nil { return approxByte, err }
+
+	// find the nearest crossing
+	best, bestDist := int64(-1), int64(-1)
+	prev := combinedSample(data)
+	for i := 4; i + 4 <= len(data); i += 4 {
+		curr := combinedSample(data[i : ])
+		if (prev <= 0 && curr >= 0) || (prev >= 0 && curr <= 0) {
+			position := start + int64(i)
+			if absInt(prev) < absInt(curr) { position -= 4 }
+			dist := position - approxByte
+			if dist < 0 { dist = -dist }
+			if bestDist == -1 || dist < bestDist { best, bestDist = position, dist }
+		}
+		prev = curr
+	}
+	if best == -1 { return approxByte, ErrNoZeroCrossing }
+	return best, nil
+}
+
+// Scans the whole stream looking for a strongly self-similar region and
+// returns candidate loop start and end points, aligned to 4 bytes, that can
+// be directly passed to [NewLooper]. The loop will be at least minLoopSeconds
+// long. This is only a heuristic meant to provide a first guess that can be
+// fine-tuned later, e.g. with the [apps/loop_finder] tool.
+//
+// The process works in two steps:
+//  - The stream is reduced to an energy envelope of blocks of 1024 samples,
+//    and the autocorrelation of the envelope is used to find the loop length
+//    and the first position where the audio starts repeating.
+//  - The loop end is then refined at sample level by comparing the raw
+//    audio around the loop start and the candidate loop end.
+//
+// The whole stream is decoded and kept in memory during the process, and the
+// autocorrelation has a cost of O(B^2), where B is the number of blocks in
+// the stream (~43 blocks per second at 44.1kHz). For a few minutes of audio
+// this takes a noticeable amount of time, so avoid calling this on the
+// audio or game loop goroutines.
+//
+// The sample rate is taken from Ebitengine's audio.CurrentContext(). If no
+// audio context has been initialized, [ErrAudioContextUninitialized] will be
+// returned. The stream's playback position is restored before returning.
+//
+// [apps/loop_finder]: https://github.com/tinne26/edau/tree/main/apps
+func FindLoopPoints(stream StdAudioStream, minLoopSeconds float64) (int64, int64, error) {
+	if minLoopSeconds <= 0 { panic("FindLoopPoints minLoopSeconds must be strictly positive") }
+	ctx := audio.CurrentContext()
+	if ctx == nil { return 0, 0, ErrAudioContextUninitialized }
+
+	// decode the stream and reduce it to mono
+	length := stream.Length()
+	data, err := readStreamRegion(stream, 0, length - (length & 0b11))
+	if err != nil { return 0, 0, err }
+	mono := make([]float32, len(data)/4)
+	for i := range mono {
+		left, right := GetSampleAsF64(data[i*4 : ])
+		mono[i] = float32((left + right)/2.0)
+	}
+	data = nil
+
+	// compute the energy envelope
+	numBlocks := len(mono)/loopFinderBlockFrames
+	minLoopFrames := int(math.Ceil(minLoopSeconds*float64(ctx.SampleRate())))
+	minLag := (minLoopFrames + loopFinderBlockFrames - 1)/loopFinderBlockFrames
+	if minLag < 1 { minLag = 1 }
+	minOverlap := minLag/4 + 1
+	if numBlocks < minLag + minOverlap {
+		return 0, 0, errors.New("stream too short to find loop points with the given minimum loop duration")
+	}
+	envelope := make([]float64, numBlocks)
+	for i := range envelope {
+		var energy float64
+		for _, sample := range mono[i*loopFinderBlockFrames : (i + 1)*loopFinderBlockFrames] {
+			energy += float64(sample)*float64(sample)
+		}
+		envelope[i] = math.Sqrt(energy/loopFinderBlockFrames)
+	}
+
+	// find the lag with the highest envelope correlation
+	bestLag, bestScore := -1, math.Inf(-1)
+	for lag := minLag; lag <= numBlocks - minOverlap; lag++ {
+		score := pearsonCorrelation(envelope[0 : numBlocks - lag], envelope[lag : ])
+		if score > bestScore { bestLag, bestScore = lag, score }
+	}
+
+	// find the first position where the audio starts repeating, using a
+	// moving average of the envelope differences at the chosen lag
+	window := minOverlap
+	diffs := make([]float64, numBlocks - bestLag)
+	for i := range diffs {
+		diffs[i] = math.Abs(envelope[i] - envelope[i + bestLag])
+	}
+	averages := make([]float64, len(diffs) - window + 1)
+	minAverage := math.Inf(1)
+	for i := range averages {
+		for _, diff := range diffs[i : i + window] { averages[i] += diff }
+		averages[i] /= float64(window)
+		minAverage = math.Min(minAverage, averages[i])
+	}
+	startBlock := 0
+	for i, average := range averages {
+		if average <= minAverage*1.5 + 1e-9 { startBlock = i ; break }
+	}
+
+	// refine the loop end at sample level
+	start := startBlock*loopFinderBlockFrames
+	end := start + bestLag*loopFinderBlockFrames
+	// (offsets are explored from the closest to the farthest, and only
+	// clearly better matches replace closer ones, as periodic signals
+	// can match almost perfectly at multiple offsets)
+	bestOffset, bestError := 0, math.Inf(1)
+	for i := 0; i <= loopFinderBlockFrames*2; i++ {
+		offset := (i + 1)/2
+		if i & 1 == 1 { offset = -offset }
+		candidate := end + offset
+		if candidate - start < minLoopFrames { continue } // refining can't shorten the loop below the minimum
+		refineLen := loopFinderRefineFrames
+		if candidate + refineLen > len(mono) { refineLen = len(mono) - candidate }
+		if refineLen <= 0 { continue }
+		var sqError float64
+		for k := 0; k < refineLen; k++ {
+			diff := float64(mono[start + k] - mono[candidate + k])
+			sqError += diff*diff
+		}
+		sqError /= float64(refineLen)
+		if sqError < bestError*0.5 - 1e-12 { bestOffset, bestError = offset, sqError }
+	}
+	end += bestOffset
+
+	return int64(start)*4, int64(end)*4, nil
+}
+
+// Returns the Pearson correlation coefficient of two slices
+// of the same length, or 0 if any of them is constant.
+func pearsonCorrelation(a, b []float64) float64 {
+	var meanA, meanB float64
+	for i := range a {
+		meanA += a[i]
+		meanB += b[i]
+	}
+	meanA /= float64(len(a))
+	meanB /= float64(len(b))
+
+	var cov, varA, varB float64
+	for i := range a {
+		da, db := a[i] - meanA, b[i] - meanB
+		cov  += da*db
+		varA += da*da
+		varB += db*db
+	}
+	if varA == 0 || varB == 0 { return 0 }
+	return cov/math.Sqrt(varA*varB)
+}
+
+// Reads the [start, end) byte region from the stream and
+// restores its playback position afterwards.
+func readStreamRegion(stream StdAudioStream, start, end int64) ([]byte, error) {
+	position, err := stream.Seek(0, io.SeekCurrent)
+	if err != nil { return nil, err }
+	_, err = stream.Seek(start, io.SeekStart)
+	if err != nil { return nil, err }
+	data := make([]byte, end - start)
+	n, err := io.ReadFull(stream, data)
+	if err == io.ErrUnexpectedEOF || err == io.EOF { err = nil }
+	_, seekErr := stream.Seek(position, io.SeekStart)
+	if err == nil { err = seekErr }
+	return data[0 : n - (n & 0b11)], err
+}
+
+func combinedSample(buffer []byte) int {
+	left, right := GetSampleAsI16(buffer)
+	return int(left) + int(right)
+}
+
+func absInt(value int) int {
+	if value < 0 { return -value }
+	return value
+}
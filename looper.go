@@ -1,8 +1,11 @@
 package edau
 
 import "io"
+import "fmt"
 import "sync"
+import "time"
 import "bytes"
+import "errors"
 
 // A tight audio looper. Unlike Ebitengine's [infinite looper], this looper doesn't require padding
 // after the end point because it doesn't perform any blending during the transition. Additionally,
@@ -19,36 +22,208 @@ type Looper struct {
 							  // but we have already past it, we still have to continue towards
 							  // the previous loop end point
 	loopEnd int64
+	frameSize int64
+	minLoopFrames int64
+	length int64 // explicit stream length, -1 if not given
+	loopCount int
+	events chan LoopEvent // nil unless requested through Events()
+
+	// fades (in frames)
+	sampleRate int
+	fadeInFrames int64
+	fadeInProgress int64
+	fadeOutFrames int64
+	fadeOutRemaining int64 // -1 if not fading out
+	stopped bool
+}
+
+// Options for [NewLooperWithOptions].
+type LooperOptions struct {
+	// Fade-in applied at the very beginning of the playback, to avoid pops
+	// when the stream doesn't start at silence. Zero disables the fade-in.
+	FadeIn time.Duration
+
+	// The sample rate of the stream, required for fades. If zero, the
+	// sample rate will be taken from [DefaultSampleRate].
+	SampleRate int
+
+	// The minimum loop length accepted by the looper, in frames. Loop points
+	// closer than this are rejected by the constructor and loop adjustments.
+	// Higher values can be useful to catch bugs, like a UI that allows moving
+	// the loop end too close to the loop start. If zero, only single frame
+	// loops are rejected, as they would make the looper seek after each frame.
+	MinLoopFrames int
+
+	// The size of each frame in bytes, for audio formats other than
+	// Ebitengine's default. For example, a mono 16-bit stream would use a
+	// frame size of 2. The loop points must be multiples of the frame size.
+	// If zero, the default frame size of 4 is used.
+	FrameSize int
+
+	// The length of the stream in bytes, returned by [Looper.Length]. This
+	// is useful for custom streams whose length is known out of band, as
+	// otherwise Length only works with some specific stream types. Must be
+	// a multiple of the frame size and can't be smaller than the loopEnd.
+	// If zero, the length is considered unknown.
+	Length int64
 }
 
+// Events emitted by a [Looper] each time a loop transition happens.
+// See [Looper.Events].
+type LoopEvent struct {
+	Index int // number of loop transitions so far, starting at 1
+	Position int64 // loop end position at which the transition occurred
+}
+
+const looperEventsBufferSize = 16
+
+// Loops shorter than this are rejected unless [LooperOptions] says otherwise.
+const defaultMinLoopFrames = 2
+
 // Creates a new tight [Looper].
 //
 // The stream must be a L16 little-endian stream with two channels (Ebitengine's
 // default audio format). loopStart and loopEnd must be multiples of 4, and loopStart
-// must be strictly smaller than loopEnd. This method will panic if any of those are
-// not respected.
+// must be strictly smaller than loopEnd, with at least two frames between them. This
+// method will panic if any of those are not respected.
 //
 // The loopEnd point is not itself included in the loop. For example, to play a full
 // audio stream in a loop, you would use NewLooper(stream, 0, stream.Length()).
 //
+// Each looper seeks its stream, so multiple loopers can't share the same stream.
+// To read the same data from multiple loopers concurrently (e.g., to render
+// waveforms in parallel), give each looper its own [io.SectionReader] over a
+// common [io.ReaderAt], like an [os.File] or a [bytes.Reader]:
+//    looper := edau.NewLooper(io.NewSectionReader(source, 0, size), loopStart, loopEnd)
+//
 // If you need help to determine the loop start and end points, see [apps/loop_finder].
 //
 // [apps/loop_finder]: https://github.com/tinne26/edau/tree/main/apps
 func NewLooper(stream io.ReadSeeker, loopStart int64, loopEnd int64) *Looper {
-	assertLoopValuesValidity(loopStart, loopEnd)
+	return newLooper(stream, loopStart, loopEnd, 4, defaultMinLoopFrames)
+}
+
+func newLooper(stream io.ReadSeeker, loopStart, loopEnd, frameSize, minLoopFrames int64) *Looper {
+	assertLoopValuesValidity(loopStart, loopEnd, frameSize, minLoopFrames)
 	return &Looper {
 		stream: stream,
 		loopStart: loopStart,
 		loopEnd: loopEnd,
 		activeLoopEnd: loopEnd,
+		frameSize: frameSize,
+		minLoopFrames: minLoopFrames,
+		fadeOutRemaining: -1,
+		length: -1,
 	}
 }
 
-// Implements [io.Reader].
+// Like [NewLooper], but with additional options. Fades assume 16-bit
+// samples, so the frame size must be even when using them. This method will
+// panic if the loop points or the options are invalid, or if a sample
+// rate is required but none is given and Ebitengine's audio context
+// hasn't been initialized.
+func NewLooperWithOptions(stream io.ReadSeeker, loopStart int64, loopEnd int64, options LooperOptions) *Looper {
+	if options.FadeIn < 0 { panic("NewLooperWithOptions FadeIn can't be negative") }
+	if options.SampleRate < 0 { panic("NewLooperWithOptions SampleRate can't be negative") }
+	if options.MinLoopFrames < 0 { panic("NewLooperWithOptions MinLoopFrames can't be negative") }
+	if options.FrameSize < 0 { panic("NewLooperWithOptions FrameSize can't be negative") }
+	if options.Length < 0 { panic("NewLooperWithOptions Length can't be negative") }
+	minLoopFrames := int64(options.MinLoopFrames)
+	if minLoopFrames == 0 { minLoopFrames = defaultMinLoopFrames }
+	frameSize := int64(options.FrameSize)
+	if frameSize == 0 { frameSize = 4 }
+	if options.FadeIn > 0 && frameSize & 0b1 != 0 {
+		panic("NewLooperWithOptions FadeIn requires an even FrameSize")
+	}
+	looper := newLooper(stream, loopStart, loopEnd, frameSize, minLoopFrames)
+	if options.Length > 0 {
+		if options.Length % frameSize != 0 { panic("NewLooperWithOptions Length must be multiple of the frame size") }
+		if loopEnd > options.Length { panic("NewLooperWithOptions loopEnd can't exceed the Length") }
+		looper.length = options.Length
+	}
+	looper.sampleRate = options.SampleRate
+	if options.FadeIn > 0 {
+		looper.fadeInFrames = SamplesForDuration(options.FadeIn, looper.getSampleRate())
+	}
+	return looper
+}
+
+// Like [NewLooper], but returns an error instead of panicking when the loop
+// points are invalid. Additionally, if the stream's length is known (see
+// [StreamLength]), the loopEnd is also checked against it, so loop points
+// beyond the end of the stream can be detected early.
+func NewLooperChecked(stream io.ReadSeeker, loopStart int64, loopEnd int64) (*Looper, error) {
+	err := checkLoopValuesValidity(loopStart, loopEnd, 4, defaultMinLoopFrames)
+	if err != nil { return nil, err }
+
+	length, known := StreamLength(stream)
+	if known && loopEnd > length {
+		return nil, fmt.Errorf("loopEnd (%d) exceeds the stream length (%d)", loopEnd, length)
+	}
+	return NewLooper(stream, loopStart, loopEnd), nil
+}
+
+// Implements [io.Reader]. Errors from the underlying stream are wrapped with
+// additional context, except for [io.EOF], which is returned as is.
 func (self *Looper) Read(buffer []byte) (int, error) {
 	self.mutex.Lock()
 	defer self.mutex.Unlock()
 
+	// simple case without fades
+	if self.stopped { return 0, io.EOF }
+	fadingIn, fadingOut := (self.fadeInProgress < self.fadeInFrames), (self.fadeOutRemaining >= 0)
+	if !fadingIn && !fadingOut { return self.read(buffer) }
+
+	// fades case: align the buffer to whole frames and apply the gains
+	buffer = buffer[0 : int64(len(buffer)) - int64(len(buffer)) % self.frameSize]
+	if fadingOut && int64(len(buffer)) > self.fadeOutRemaining*self.frameSize {
+		buffer = buffer[0 : self.fadeOutRemaining*self.frameSize]
+	}
+	n, err := self.read(buffer)
+	for i := int64(0); i + self.frameSize <= int64(n); i += self.frameSize {
+		gain := 1.0
+		if self.fadeInProgress < self.fadeInFrames {
+			gain = float64(self.fadeInProgress)/float64(self.fadeInFrames)
+			self.fadeInProgress += 1
+		}
+		if self.fadeOutRemaining > 0 {
+			gain *= float64(self.fadeOutRemaining)/float64(self.fadeOutFrames + 1)
+			self.fadeOutRemaining -= 1
+		}
+		applyL16Gain(buffer[i : i + self.frameSize], gain)
+	}
+	if self.fadeOutRemaining == 0 {
+		self.stopped = true
+		return n, io.EOF
+	}
+	return n, err
+}
+
+// Starts fading out the audio over the given duration. Once the fade out
+// finishes, Read returns [io.EOF]. If the duration is zero, the looper is
+// stopped immediately. Seeking doesn't cancel the fade out or restart the
+// playback after it has stopped. Fades assume 16-bit samples.
+//
+// The sample rate is taken from the [LooperOptions] if available, or from
+// [DefaultSampleRate] otherwise. This method will panic if the duration
+// is negative or the sample rate can't be determined.
+func (self *Looper) FadeOutAndStop(duration time.Duration) {
+	if duration < 0 { panic("FadeOutAndStop duration can't be negative") }
+	sampleRate := self.getSampleRate() // sampleRate is immutable
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	frames := SamplesForDuration(duration, sampleRate)
+	if self.fadeOutRemaining >= 0 && self.fadeOutRemaining < frames { return } // already fading out faster
+	if frames == 0 { self.stopped = true }
+	self.fadeOutFrames, self.fadeOutRemaining = frames, frames
+}
+
+func (self *Looper) getSampleRate() int {
+	return resolveSampleRate(self.sampleRate, "Looper")
+}
+
+// Read without fades.
+func (self *Looper) read(buffer []byte) (int, error) {
 	var bytesRead int
 	for len(buffer) > 0 {
 		untilNextLoop := self.activeLoopEnd - self.position
@@ -69,9 +244,11 @@ func (self *Looper) Read(buffer []byte) (int, error) {
 		}
 	
 		var err error
+		self.loopCount += 1
+		self.emitEvent(LoopEvent{ Index: self.loopCount, Position: self.activeLoopEnd })
 		self.activeLoopEnd = self.loopEnd
 		self.position, err = self.stream.Seek(self.loopStart, io.SeekStart)
-		if err != nil { return bytesRead, err }
+		if err != nil { return bytesRead, fmt.Errorf("looper: failed to seek to loop start: %w", err) }
 		buffer = buffer[untilNextLoop : ]
 	}
 
@@ -86,6 +263,9 @@ func (self *Looper) readAll(buffer []byte) (int, error) {
 		// read and return if we are done or got an error
 		n, err := self.stream.Read(buffer)
 		bytesRead += n
+		if err != nil && err != io.EOF {
+			return bytesRead, fmt.Errorf("looper: failed to read at position %d: %w", self.position + int64(bytesRead), err)
+		}
 		if n == len(buffer) || err != nil {
 			return bytesRead, err
 		}
@@ -95,22 +275,88 @@ func (self *Looper) readAll(buffer []byte) (int, error) {
 	}
 }
 
+// Returns a channel where a [LoopEvent] is sent each time the loop end is
+// reached and playback jumps back to the loop start. The channel is created
+// on the first call, and subsequent calls return the same channel.
+//
+// The channel is buffered, and if the consumer is too slow and the buffer
+// becomes full, new events are dropped. Reads are never blocked. To stop
+// receiving events, see [Looper.StopEvents].
+func (self *Looper) Events() <-chan LoopEvent {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	if self.events == nil {
+		self.events = make(chan LoopEvent, looperEventsBufferSize)
+	}
+	return self.events
+}
+
+// Stops emitting loop events and closes the channel returned by
+// [Looper.Events], so goroutines ranging over it can finish. Calling
+// [Looper.Events] again afterwards will create a new channel.
+func (self *Looper) StopEvents() {
+	self.mutex.Lock()
+	if self.events != nil {
+		close(self.events)
+		self.events = nil
+	}
+	self.mutex.Unlock()
+}
+
+// Sends the event if there's an events channel and it has space.
+func (self *Looper) emitEvent(event LoopEvent) {
+	if self.events == nil { return }
+	select {
+	case self.events <- event:
+	default: // consumer too slow, drop event
+	}
+}
+
 // Seek seeks directly on the underlying stream. It is the caller's 
 // responsibility to make sure the seek falls inside the current loop
-// (if that's desired).
+// (if that's desired). Seeks before the loop start will play until the
+// loop end and then start looping as usual, while seeks to the loop end
+// or beyond it will make the next read continue directly from the loop
+// start. In both cases, any pending loop end from previous calls to
+// [Looper.AdjustLoop] is discarded.
+//
+// Seek(0, io.SeekCurrent) can be used to query the current position
+// without any side effects.
 func (self *Looper) Seek(offset int64, whence int) (int64, error) {
 	self.mutex.Lock()
-	n, err := self.stream.Seek(offset, whence)
-	self.position = n
-	if self.position <= self.loopEnd {
-		self.activeLoopEnd = self.loopEnd
-	}
-	self.mutex.Unlock()
-	return n, err
+	defer self.mutex.Unlock()
+	return self.seek(offset, whence)
+}
+
+// Seeks to the currently configured loop starting point.
+func (self *Looper) SeekToLoopStart() (int64, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	return self.seek(self.loopStart, io.SeekStart)
+}
+
+// Seeks to the currently configured loop ending point. Since the
+// loop end is not itself included in the loop, the next read will
+// continue directly from the loop start.
+func (self *Looper) SeekToLoopEnd() (int64, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	return self.seek(self.loopEnd, io.SeekStart)
+}
+
+func (self *Looper) seek(offset int64, whence int) (int64, error) {
+	if whence == io.SeekCurrent && offset == 0 { return self.position, nil }
+	position, err := self.stream.Seek(offset, whence)
+	if err != nil { return position, err }
+	self.position = position
+	self.activeLoopEnd = self.loopEnd
+	return position, nil
 }
 
 // Returns the current playback position. The value will always be multiple
-// of 4, as in Ebitengine each sample is composed of 4 bytes.
+// of the frame size (4 by default, as in Ebitengine each sample is composed
+// of 4 bytes), unless the position was set to a misaligned value with
+// [Looper.Seek].
 func (self *Looper) GetPosition() int64 {
 	self.mutex.Lock()
 	position := self.position
@@ -134,6 +380,18 @@ func (self *Looper) GetLoopEnd() int64 {
 	return loopEnd
 }
 
+// Returns the loop ending point that the playback is currently heading
+// to. This is usually the same as [Looper.GetLoopEnd], but after calling
+// [Looper.AdjustLoop] with a loop end placed before the current position,
+// the playback will continue until the previous loop end, which is the
+// value returned by this method until the loop transition happens.
+func (self *Looper) GetActiveLoopEnd() int64 {
+	self.mutex.Lock()
+	activeLoopEnd := self.activeLoopEnd
+	self.mutex.Unlock()
+	return activeLoopEnd
+}
+
 // Like [Looper.GetLoopStart] and [Looper.GetLoopEnd], but both at once.
 func (self *Looper) GetLoopPoints() (int64, int64) {
 	self.mutex.Lock()
@@ -143,15 +401,91 @@ func (self *Looper) GetLoopPoints() (int64, int64) {
 	return loopStart, loopEnd
 }
 
+// Returns the current playback position relative to the loop start, in bytes.
+// The value is clamped to the loop body, so it will be 0 while the playback
+// position is still before the loop start.
+//
+// If the loop points were adjusted and the playback is still heading to the
+// previous loop end (see [Looper.AdjustLoop]), that previous end point is
+// used as the end of the loop body.
+func (self *Looper) GetLoopElapsed() int64 {
+	self.mutex.Lock()
+	elapsed, _ := self.loopElapsed()
+	self.mutex.Unlock()
+	return elapsed
+}
+
+// Returns the current playback progress within the loop body, in [0, 1].
+// See [Looper.GetLoopElapsed] for further details.
+func (self *Looper) GetLoopProgress() float64 {
+	self.mutex.Lock()
+	elapsed, loopLen := self.loopElapsed()
+	self.mutex.Unlock()
+	return float64(elapsed)/float64(loopLen)
+}
+
+// Reads and returns the frames around the given byte offset, normalized to
+// [-1, 1], without affecting the playback position. The result contains
+// framesBefore frames before the offset, followed by framesAfter frames
+// starting at the offset. Frames outside the configured loop are returned
+// as zeros, which is useful to display the waveform around loop points.
+//
+// The byte offset must be multiple of 4 and the looper must use the default
+// frame size (see [LooperOptions]), as frames are read as L16 stereo
+// samples. This method will panic otherwise, or if any frame count is negative.
+func (self *Looper) PeekAround(byteOffset int64, framesBefore, framesAfter int) ([][2]float64, error) {
+	if framesBefore < 0 || framesAfter < 0 { panic("PeekAround frame counts can't be negative") }
+	if byteOffset & 0b11 != 0 { panic("PeekAround byteOffset must be multiple of 4") }
+	if self.frameSize != 4 { panic("PeekAround requires a frame size of 4") } // frameSize is immutable
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	// determine the region to read, clamped to the loop
+	frames := make([][2]float64, framesBefore + framesAfter)
+	start := byteOffset - int64(framesBefore)*4
+	end := byteOffset + int64(framesAfter)*4
+	readStart, readEnd := start, end
+	if readStart < self.loopStart { readStart = self.loopStart }
+	if readEnd > self.loopEnd { readEnd = self.loopEnd }
+	if readStart >= readEnd { return frames, nil }
+
+	// read the region and restore the playback position
+	_, err := self.stream.Seek(readStart, io.SeekStart)
+	if err != nil { return nil, err }
+	data := make([]byte, readEnd - readStart)
+	_, err = io.ReadFull(self.stream, data)
+	_, seekErr := self.stream.Seek(self.position, io.SeekStart)
+	if err != nil { return nil, fmt.Errorf("looper: failed to peek at position %d: %w", readStart, err) }
+	if seekErr != nil { return nil, seekErr }
+
+	offset := int((readStart - start)/4)
+	for i := 0; i < len(data)/4; i++ {
+		ReadNormalizedFrameInto(data[i*4 : ], &frames[offset + i])
+	}
+	return frames, nil
+}
+
+// Returns the clamped elapsed bytes and the length of the active loop body.
+func (self *Looper) loopElapsed() (int64, int64) {
+	loopLen := self.activeLoopEnd - self.loopStart
+	if loopLen <= 0 { loopLen = self.loopEnd - self.loopStart }
+	elapsed := self.position - self.loopStart
+	if elapsed < 0 { elapsed = 0 }
+	if elapsed > loopLen { elapsed = loopLen }
+	return elapsed, loopLen
+}
+
 // Sets new values for the loop starting and ending points. The values are
 // []byte indices. Therefore, since Ebitengine audio samples require 4 bytes
-// each, the passed start and end points must also be multiples of 4.
+// each, the passed start and end points must also be multiples of 4 (or
+// the frame size configured in [LooperOptions]), and the loop
+// can't be shorter than [Looper.MinLoopFrames].
 //
 // If the new loop end is set before the current playback position, the loop
 // will continue playing until the previously configured end point before
 // the new loop comes into effect.
 func (self *Looper) AdjustLoop(loopStart, loopEnd int64) {
-	assertLoopValuesValidity(loopStart, loopEnd)
+	assertLoopValuesValidity(loopStart, loopEnd, self.frameSize, self.minLoopFrames) // immutable values
 	self.mutex.Lock()
 	self.loopStart = loopStart
 	self.loopEnd = loopEnd
@@ -161,31 +495,65 @@ func (self *Looper) AdjustLoop(loopStart, loopEnd int64) {
 	self.mutex.Unlock()
 }
 
-// Returns the underlying stream's length. The underlying stream must
-// have a Length() int64 method or be a [bytes.Reader]. This method
-// will panic otherwise.
+// Like [Looper.AdjustLoop], but returns an error describing the problem
+// instead of panicking if the loop points are invalid. In that case, the
+// loop points are left unmodified. This is safer when the loop points come
+// from interactive sources, like UI sliders.
+func (self *Looper) TryAdjustLoop(loopStart, loopEnd int64) error {
+	err := checkLoopValuesValidity(loopStart, loopEnd, self.frameSize, self.minLoopFrames) // immutable values
+	if err != nil { return err }
+	self.AdjustLoop(loopStart, loopEnd)
+	return nil
+}
+
+// Returns the underlying stream's length. If a length was given through
+// [LooperOptions], that length is returned. Otherwise,
+// the underlying stream must have a Length() int64 method or be a
+// [bytes.Reader] or [io.SectionReader]. This method will panic otherwise.
 func (self *Looper) Length() int64 {
 	self.mutex.Lock()
-	var length int64
+	defer self.mutex.Unlock()
+	if self.length >= 0 { return self.length }
 	switch streamWithLen := self.stream.(type) {
 	case *bytes.Reader:
-		length = int64(streamWithLen.Len())
+		return streamWithLen.Size() // Len() would only count the unread bytes
+	case *io.SectionReader:
+		return streamWithLen.Size()
 	case StdAudioStream:
-		length = streamWithLen.Length()
+		return streamWithLen.Length()
 	default:
 		panic("Looper underlying stream doesn't implement Length() int64 and is not a *bytes.Reader either")
 	}
-	self.mutex.Unlock()
-	return length
-}
-
-func assertLoopValuesValidity(loopStart, loopEnd int64) {
-	if loopStart & 0b11 != 0 { panic("loopStart must be multiple of 4") }
-	if loopEnd   & 0b11 != 0 { panic("loopEnd must be multiple of 4") }
-	if loopStart >= loopEnd { panic("loopStart must be strictly smaller than loopEnd") }
-	if loopStart < 0 { panic("loopStart must be >= 0") }
-	// Note: technically loopStart can be loopEnd - 4 or similar extremely short distances.
-	//       This is allowed but it's not really correct. Nothing will sound and the looper
-	//       is likely to start lagging unless absurd sample rates are used. Other small
-	//       loop lengths are equally likely to cause trouble, but that's on the user.
-}
\ No newline at end of file
+}
+
+// Returns the minimum loop length accepted by the looper, in frames.
+// See [LooperOptions].
+func (self *Looper) MinLoopFrames() int {
+	return int(self.minLoopFrames) // immutable
+}
+
+func assertLoopValuesValidity(loopStart, loopEnd, frameSize, minLoopFrames int64) {
+	err := checkLoopValuesValidity(loopStart, loopEnd, frameSize, minLoopFrames)
+	if err != nil { panic(err.Error()) }
+}
+
+func checkLoopValuesValidity(loopStart, loopEnd, frameSize, minLoopFrames int64) error {
+	if loopStart % frameSize != 0 { return fmt.Errorf("loopStart must be multiple of the frame size (%d)", frameSize) }
+	if loopEnd   % frameSize != 0 { return fmt.Errorf("loopEnd must be multiple of the frame size (%d)", frameSize) }
+	if loopStart >= loopEnd { return errors.New("loopStart must be strictly smaller than loopEnd") }
+	if loopStart < 0 { return errors.New("loopStart must be >= 0") }
+	if (loopEnd - loopStart)/frameSize < minLoopFrames {
+		return fmt.Errorf("loop length (%d frames) is below the minimum (%d frames)", (loopEnd - loopStart)/frameSize, minLoopFrames)
+	}
+	return nil
+}
+
+// Multiplies the 16-bit little-endian samples in the
+// given buffer by the given gain, in place.
+func applyL16Gain(buffer []byte, gain float64) {
+	for i := 0; i + 2 <= len(buffer); i += 2 {
+		sample := (int16(buffer[i + 1]) << 8) | int16(buffer[i])
+		sample = clipFloatToI16(float64(sample)*gain)
+		buffer[i], buffer[i + 1] = byte(sample), byte(sample >> 8)
+	}
+}
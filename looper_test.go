@@ -0,0 +1,249 @@
+package edau
+
+import "io"
+import "bytes"
+import "time"
+import "testing"
+import "encoding/binary"
+
+func TestLooperBasic(t *testing.T) {
+	looper := NewLooper(testLooperStream(8), 2*4, 5*4)
+	testLooperExpect(t, looper, 4, []uint32{ 0, 1, 2, 3, 4, 2, 3, 4, 2, 3 })
+}
+
+func TestLooperReadSizes(t *testing.T) {
+	// reads spanning multiple loops at once
+	expected := []uint32{ 0, 1, 2, 3, 4, 2, 3, 4, 2, 3, 4, 2, 3, 4, 2, 3, 4, 2 }
+	for _, readSize := range []int{ 4, 8, 12, 20, 36 } {
+		looper := NewLooper(testLooperStream(8), 2*4, 5*4)
+		testLooperExpect(t, looper, readSize, expected)
+	}
+}
+
+func TestLooperSeek(t *testing.T) {
+	tests := []struct {
+		name string
+		seekFrame int64
+		expected []uint32
+	}{
+		{ "before loop start", 1, []uint32{ 1, 2, 3, 4, 2, 3 } },
+		{ "at loop start"    , 2, []uint32{ 2, 3, 4, 2, 3, 4 } },
+		{ "inside loop"      , 3, []uint32{ 3, 4, 2, 3, 4, 2 } },
+		{ "at loop end"      , 5, []uint32{ 2, 3, 4, 2, 3, 4 } },
+		{ "beyond loop end"  , 7, []uint32{ 2, 3, 4, 2, 3, 4 } },
+	}
+	for _, test := range tests {
+		looper := NewLooper(testLooperStream(8), 2*4, 5*4)
+		testLooperExpect(t, looper, 4, []uint32{ 0, 1, 2 })
+		position, err := looper.Seek(test.seekFrame*4, io.SeekStart)
+		if err != nil || position != test.seekFrame*4 {
+			t.Fatalf("%s: unexpected seek result (%d, %v)", test.name, position, err)
+		}
+		if looper.GetPosition() != test.seekFrame*4 {
+			t.Fatalf("%s: expected position %d, got %d", test.name, test.seekFrame*4, looper.GetPosition())
+		}
+		testLooperExpect(t, looper, 4, test.expected)
+	}
+}
+
+func TestLooperSeekToLoopPoints(t *testing.T) {
+	looper := NewLooper(testLooperStream(8), 2*4, 5*4)
+	looper.SeekToLoopEnd()
+	testLooperExpect(t, looper, 4, []uint32{ 2, 3 })
+	looper.SeekToLoopStart()
+	testLooperExpect(t, looper, 4, []uint32{ 2, 3, 4, 2 })
+}
+
+func TestLooperAdjustLoop(t *testing.T) {
+	// shrinking the loop end behind the current position
+	// keeps playing until the previous loop end
+	looper := NewLooper(testLooperStream(10), 2*4, 7*4)
+	testLooperExpect(t, looper, 4, []uint32{ 0, 1, 2, 3, 4, 5 })
+	looper.AdjustLoop(1*4, 3*4)
+	testLooperExpect(t, looper, 4, []uint32{ 6, 1, 2, 1, 2 })
+
+	// a query seek doesn't discard the pending loop end
+	looper = NewLooper(testLooperStream(10), 2*4, 7*4)
+	testLooperExpect(t, looper, 4, []uint32{ 0, 1, 2, 3, 4, 5 })
+	looper.AdjustLoop(1*4, 3*4)
+	position, _ := looper.Seek(0, io.SeekCurrent)
+	if position != 6*4 { t.Fatalf("expected position %d, got %d", 6*4, position) }
+	testLooperExpect(t, looper, 4, []uint32{ 6, 1, 2 })
+
+	// seeking beyond the new loop end discards the pending loop end
+	looper = NewLooper(testLooperStream(10), 2*4, 7*4)
+	testLooperExpect(t, looper, 4, []uint32{ 0, 1, 2, 3, 4, 5 })
+	looper.AdjustLoop(1*4, 3*4)
+	looper.Seek(5*4, io.SeekStart)
+	testLooperExpect(t, looper, 4, []uint32{ 1, 2, 1, 2 })
+
+	// extending the loop end ahead of the current position
+	looper = NewLooper(testLooperStream(10), 2*4, 5*4)
+	testLooperExpect(t, looper, 4, []uint32{ 0, 1, 2, 3 })
+	looper.AdjustLoop(2*4, 8*4)
+	testLooperExpect(t, looper, 4, []uint32{ 4, 5, 6, 7, 2, 3 })
+}
+
+func TestLooperFades(t *testing.T) {
+	data := make([]byte, 100*4)
+	for i := 0; i < len(data); i += 4 { StoreL16Sample(data[i : ], 10000, -10000) }
+	options := LooperOptions{ FadeIn: 10*time.Millisecond, SampleRate: 1000 }
+	looper := NewLooperWithOptions(bytes.NewReader(data), 0, 100*4, options)
+
+	// fade in
+	buffer := make([]byte, 20*4)
+	n, err := looper.Read(buffer)
+	if n != len(buffer) || err != nil { t.Fatalf("unexpected read result (%d, %v)", n, err) }
+	for i, expected := range []int16{ 0, 1000, 2000, 3000, 4000, 5000, 6000, 7000, 8000, 9000, 10000, 10000 } {
+		left, right := GetSampleAsI16(buffer[i*4 : ])
+		if left != expected || right != -expected {
+			t.Fatalf("fade in frame %d: expected (%d, %d), got (%d, %d)", i, expected, -expected, left, right)
+		}
+	}
+
+	// fade out and stop
+	looper.FadeOutAndStop(4*time.Millisecond)
+	n, err = looper.Read(buffer)
+	if n != 4*4 || err != io.EOF { t.Fatalf("expected (%d, EOF) on fade out, got (%d, %v)", 4*4, n, err) }
+	for i, expected := range []int16{ 8000, 6000, 4000, 2000 } {
+		left, _ := GetSampleAsI16(buffer[i*4 : ])
+		if left != expected { t.Fatalf("fade out frame %d: expected %d, got %d", i, expected, left) }
+	}
+	n, err = looper.Read(buffer)
+	if n != 0 || err != io.EOF { t.Fatalf("expected (0, EOF) after stopping, got (%d, %v)", n, err) }
+}
+
+// --- helper functions ---
+
+func TestLooperMinLength(t *testing.T) {
+	// the default only rejects single frame loops
+	_, err := NewLooperChecked(testLooperStream(10), 2*4, 3*4)
+	if err == nil { t.Fatal("expected error for a single frame loop") }
+	_, err = NewLooperChecked(testLooperStream(10), 2*4, 4*4)
+	if err != nil { t.Fatal(err) }
+
+	// custom minimum
+	options := LooperOptions{ MinLoopFrames: 4 }
+	looper := NewLooperWithOptions(testLooperStream(10), 2*4, 6*4, options)
+	if looper.MinLoopFrames() != 4 { t.Fatalf("expected MinLoopFrames 4, got %d", looper.MinLoopFrames()) }
+	err = looper.TryAdjustLoop(4*4, 7*4)
+	if err == nil { t.Fatal("expected error when adjusting loop below the minimum length") }
+	if looper.GetLoopStart() != 2*4 || looper.GetLoopEnd() != 6*4 {
+		t.Fatalf("failed adjustment modified the loop points")
+	}
+
+	// panicking variants
+	func() {
+		defer func() {
+			if recover() == nil { t.Fatal("expected AdjustLoop panic") }
+		}()
+		looper.AdjustLoop(0, 3*4)
+	}()
+	func() {
+		defer func() {
+			if recover() == nil { t.Fatal("expected NewLooperWithOptions panic") }
+		}()
+		NewLooperWithOptions(testLooperStream(10), 0, 3*4, options)
+	}()
+}
+
+func TestLooperSharedReaderAt(t *testing.T) {
+	// loopers sharing the same source keep independent positions
+	source := testLooperStream(10)
+	first  := NewLooper(io.NewSectionReader(source, 0, source.Size()), 2*4, 5*4)
+	second := NewLooper(io.NewSectionReader(source, 0, source.Size()), 4*4, 8*4)
+	if first.Length() != 10*4 { t.Fatalf("expected length %d, got %d", 10*4, first.Length()) }
+	_, err := second.Seek(6*4, io.SeekStart)
+	if err != nil { t.Fatal(err) }
+	testLooperExpect(t, first, 4, []uint32{0, 1, 2})
+	testLooperExpect(t, second, 4, []uint32{6, 7, 4})
+	testLooperExpect(t, first, 4, []uint32{3, 4, 2, 3})
+	testLooperExpect(t, second, 4, []uint32{5, 6, 7, 4})
+}
+
+func TestLooperWithLength(t *testing.T) {
+	stream := struct{ io.ReadSeeker }{ testLooperStream(10) } // hide the concrete type
+	looper := NewLooperWithOptions(stream, 2*4, 5*4, LooperOptions{ Length: 10*4 })
+	if looper.Length() != 10*4 { t.Fatalf("expected length %d, got %d", 10*4, looper.Length()) }
+	testLooperExpect(t, looper, 4, []uint32{0, 1, 2, 3, 4, 2, 3})
+}
+
+func TestLooperFrameSize(t *testing.T) {
+	looper := NewLooperWithOptions(testLooperStream(10), 2*8, 4*8, LooperOptions{ FrameSize: 8 })
+	testLooperExpect(t, looper, 8, []uint32{0, 1, 2, 3, 4, 5, 6, 7, 4, 5, 6, 7})
+	err := looper.TryAdjustLoop(1*4, 4*8)
+	if err == nil { t.Fatal("expected error for loop start not multiple of the frame size") }
+}
+
+func TestLooperLengthAfterSeek(t *testing.T) {
+	// *bytes.Reader is special-cased, as it doesn't have a Length() method
+	pcm := NewPCMBuffer(make([]byte, 10*4))
+	_, _ = io.ReadFull(testLooperStream(10), pcm.Bytes())
+	for _, stream := range []io.ReadSeeker{ testLooperStream(10), pcm } {
+		looper := NewLooper(stream, 2*4, 5*4)
+		_, err := looper.Seek(3*4, io.SeekStart)
+		if err != nil { t.Fatal(err) }
+		testLooperExpect(t, looper, 4, []uint32{3, 4})
+		if looper.Length() != 10*4 { t.Fatalf("expected length %d after seek, got %d", 10*4, looper.Length()) }
+	}
+}
+
+func TestLooperPeekAround(t *testing.T) {
+	data := make([]byte, 20*4)
+	for i := 0; i < 20; i++ {
+		StoreL16Sample(data[i*4 : ], int16(i*100), int16(-i*100))
+	}
+	looper := NewLooper(bytes.NewReader(data), 4*4, 12*4)
+	_, err := io.ReadFull(looper, make([]byte, 3*4)) // move the position a bit
+	if err != nil { t.Fatal(err) }
+
+	// window crossing the loop start, so the first two frames are zero padded
+	frames, err := looper.PeekAround(6*4, 4, 3)
+	if err != nil { t.Fatal(err) }
+	if len(frames) != 7 { t.Fatalf("expected 7 frames, got %d", len(frames)) }
+	for i, frame := range frames {
+		var expected [2]float64
+		if i >= 2 { expected[0], expected[1] = GetSampleAsF64(data[(i + 2)*4 : ]) }
+		if frame != expected { t.Fatalf("frame %d: expected %v, got %v", i, expected, frame) }
+	}
+
+	// window crossing the loop end
+	frames, err = looper.PeekAround(12*4, 2, 2)
+	if err != nil { t.Fatal(err) }
+	left, right := GetSampleAsF64(data[11*4 : ])
+	if frames[1] != [2]float64{ left, right } || frames[2] != [2]float64{ 0, 0 } {
+		t.Fatalf("unexpected frames around loop end: %v", frames)
+	}
+
+	// the playback position must be preserved
+	if looper.GetPosition() != 3*4 { t.Fatalf("expected position %d, got %d", 3*4, looper.GetPosition()) }
+	buffer := make([]byte, 4)
+	_, err = io.ReadFull(looper, buffer)
+	if err != nil { t.Fatal(err) }
+	if !bytes.Equal(buffer, data[3*4 : 4*4]) { t.Fatalf("expected playback to continue at frame 3") }
+}
+
+// Creates a stream where each sample stores its own frame index.
+func testLooperStream(frames int) *bytes.Reader {
+	data := make([]byte, frames*4)
+	for i := 0; i < frames; i++ {
+		binary.LittleEndian.PutUint32(data[i*4 : ], uint32(i))
+	}
+	return bytes.NewReader(data)
+}
+
+func testLooperExpect(t *testing.T, looper *Looper, readSize int, expected []uint32) {
+	t.Helper()
+	buffer := make([]byte, len(expected)*4)
+	for offset := 0; offset < len(buffer); {
+		end := offset + readSize
+		if end > len(buffer) { end = len(buffer) }
+		n, err := looper.Read(buffer[offset : end])
+		if err != nil { t.Fatal(err) }
+		offset += n
+	}
+	for i, frame := range expected {
+		got := binary.LittleEndian.Uint32(buffer[i*4 : ])
+		if got != frame { t.Fatalf("expected frames %v, got mismatch at index %d (%d)", expected, i, got) }
+	}
+}
@@ -0,0 +1,139 @@
+package edau
+
+import "io"
+import "math"
+
+const loudnessSubBlockMs = 100 // gating blocks are made of 4 sub-blocks (400ms, 75% overlap)
+const loudnessAbsoluteGate = -70.0 // LUFS
+const loudnessRelativeGate = -10.0 // LU, relative to the absolute-gated loudness
+
+// Measures the integrated loudness of the given stream, in LUFS, following
+// a simplified version of EBU R128 / ITU-R BS.1770: the signal is K-weighted,
+// split into 400ms blocks with 75% overlap, and the blocks are gated first
+// with an absolute threshold of -70 LUFS and then with a relative threshold
+// 10 LU below the loudness of the blocks that passed the absolute gate.
+//
+// Unlike peak measurements, loudness correlates with how loud the audio is
+// perceived, so it can be used to match the loudness of different tracks.
+// For example, if a track measures -18 LUFS and the target is -23 LUFS, the
+// track should be attenuated by 5dB.
+//
+// The whole stream is measured, starting from the beginning, and the stream
+// position is restored afterwards. If the stream is too short or too quiet
+// for any block to pass the gates, -Inf is returned. The sampleRate must
+// be strictly positive. This method will panic otherwise.
+func MeasureLoudness(stream StdAudioStream, sampleRate int) (float64, error) {
+	if sampleRate <= 0 { panic("MeasureLoudness sampleRate must be strictly positive") }
+
+	position, err := stream.Seek(0, io.SeekCurrent)
+	if err != nil { return 0, err }
+	_, err = stream.Seek(0, io.SeekStart)
+	if err != nil { return 0, err }
+
+	// K-weighting filters
+	var shelf, highPass biquadFilter
+	shelf.coeffs, highPass.coeffs = newKWeightingCoeffs(sampleRate)
+
+	// accumulate the mean square of each sub-block (both channels summed)
+	subBlockFrames := (sampleRate*loudnessSubBlockMs)/1000
+	if subBlockFrames == 0 { subBlockFrames = 1 }
+	var subBlocks []float64
+	var energy float64
+	var frames int
+	buffer := make([]byte, 16384)
+	for {
+		n, err := readFrames(stream, buffer)
+		for i := 0; i < n; i += 4 {
+			left, right := GetSampleAsF64(buffer[i : ])
+			left  = highPass.left.Next(&highPass.coeffs, shelf.left.Next(&shelf.coeffs, left))
+			right = highPass.right.Next(&highPass.coeffs, shelf.right.Next(&shelf.coeffs, right))
+			energy += left*left + right*right
+			frames += 1
+			if frames == subBlockFrames {
+				subBlocks = append(subBlocks, energy/float64(subBlockFrames))
+				energy, frames = 0, 0
+			}
+		}
+		if err == io.EOF { break }
+		if err != nil { return 0, err }
+	}
+
+	_, err = stream.Seek(position, io.SeekStart)
+	if err != nil { return 0, err }
+	return gatedLoudness(subBlocks), nil
+}
+
+// Computes the integrated loudness from the mean squares of consecutive
+// sub-blocks, applying the absolute and relative gates.
+func gatedLoudness(subBlocks []float64) float64 {
+	if len(subBlocks) < 4 { return math.Inf(-1) }
+
+	// block mean squares
+	blocks := make([]float64, len(subBlocks) - 3)
+	for i := range blocks {
+		blocks[i] = (subBlocks[i] + subBlocks[i + 1] + subBlocks[i + 2] + subBlocks[i + 3])/4
+	}
+
+	// absolute gate, then relative gate
+	absGatedMean := gatedMeanSquare(blocks, loudnessAbsoluteGate)
+	if absGatedMean == 0 { return math.Inf(-1) }
+	threshold := loudnessFromMeanSquare(absGatedMean) + loudnessRelativeGate
+	relGatedMean := gatedMeanSquare(blocks, threshold)
+	if relGatedMean == 0 { return math.Inf(-1) }
+	return loudnessFromMeanSquare(relGatedMean)
+}
+
+// Returns the mean of the blocks whose loudness is above the threshold,
+// or zero if no block passes the threshold.
+func gatedMeanSquare(blocks []float64, threshold float64) float64 {
+	var sum float64
+	var count int
+	for _, block := range blocks {
+		if loudnessFromMeanSquare(block) > threshold {
+			sum += block
+			count += 1
+		}
+	}
+	if count == 0 { return 0 }
+	return sum/float64(count)
+}
+
+func loudnessFromMeanSquare(meanSquare float64) float64 {
+	return -0.691 + 10*math.Log10(meanSquare)
+}
+
+// Returns the coefficients for the two stages of the K-weighting filter,
+// a high shelf that models the acoustic effect of the head and a high-pass
+// that models the reduced sensitivity to low frequencies. The formulas
+// adapt the BS.1770 coefficients (defined at 48kHz) to any sample rate.
+func newKWeightingCoeffs(sampleRate int) (biquadCoeffs, biquadCoeffs) {
+	// high shelf
+	const shelfFreq = 1681.974450955533
+	const shelfGainDb = 3.999843853973347
+	const shelfQ = 0.7071752369554196
+	k := math.Tan(math.Pi*shelfFreq/float64(sampleRate))
+	vh := math.Pow(10, shelfGainDb/20.0)
+	vb := math.Pow(vh, 0.4996667741545416)
+	a0 := 1.0 + k/shelfQ + k*k
+	shelf := biquadCoeffs {
+		b0: (vh + vb*k/shelfQ + k*k)/a0,
+		b1: 2.0*(k*k - vh)/a0,
+		b2: (vh - vb*k/shelfQ + k*k)/a0,
+		a1: 2.0*(k*k - 1.0)/a0,
+		a2: (1.0 - k/shelfQ + k*k)/a0,
+	}
+
+	// high-pass
+	const highPassFreq = 38.13547087602444
+	const highPassQ = 0.5003270373238773
+	k = math.Tan(math.Pi*highPassFreq/float64(sampleRate))
+	a0 = 1.0 + k/highPassQ + k*k
+	highPass := biquadCoeffs {
+		b0: 1.0,
+		b1: -2.0,
+		b2: 1.0,
+		a1: 2.0*(k*k - 1.0)/a0,
+		a2: (1.0 - k/highPassQ + k*k)/a0,
+	}
+	return shelf, highPass
+}
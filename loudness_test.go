@@ -0,0 +1,50 @@
+package edau
+
+import "io"
+import "math"
+import "bytes"
+import "time"
+import "testing"
+
+func TestMeasureLoudness(t *testing.T) {
+	// a 1kHz stereo sine with amplitude A measures close to 20*log10(A) LUFS
+	const sampleRate = 48000
+	tone, err := io.ReadAll(NewTone(1000, 0.1, 3*time.Second, sampleRate))
+	if err != nil { t.Fatal(err) }
+	stream := testAudioStream{ bytes.NewReader(tone) }
+	_, err = stream.Seek(400, io.SeekStart)
+	if err != nil { t.Fatal(err) }
+	loudness, err := MeasureLoudness(stream, sampleRate)
+	if err != nil { t.Fatal(err) }
+	if math.Abs(loudness - (-20.0)) > 0.1 { t.Fatalf("expected loudness around -20 LUFS, got %f", loudness) }
+	position, _ := stream.Seek(0, io.SeekCurrent)
+	if position != 400 { t.Fatalf("expected stream position to be restored to 400, got %d", position) }
+
+	// silence is gated out, so adding it barely changes the result. without
+	// gating, the loudness would drop by more than 2 LU. the blocks at the
+	// transition from the tone to silence still pass the gates, though
+	silence, err := io.ReadAll(NewSilence(2*time.Second, sampleRate))
+	if err != nil { t.Fatal(err) }
+	gated, err := MeasureLoudness(testAudioStream{ bytes.NewReader(append(tone, silence...)) }, sampleRate)
+	if err != nil { t.Fatal(err) }
+	if math.Abs(gated - loudness) > 0.5 { t.Fatalf("expected silence to be gated (%f vs %f)", gated, loudness) }
+	silent, err := MeasureLoudness(testAudioStream{ bytes.NewReader(silence) }, sampleRate)
+	if err != nil { t.Fatal(err) }
+	if !math.IsInf(silent, -1) { t.Fatalf("expected -Inf loudness for silence, got %f", silent) }
+
+	// low frequencies are weighted down
+	bass, err := io.ReadAll(NewTone(30, 0.1, 3*time.Second, sampleRate))
+	if err != nil { t.Fatal(err) }
+	bassLoudness, err := MeasureLoudness(testAudioStream{ bytes.NewReader(bass) }, sampleRate)
+	if err != nil { t.Fatal(err) }
+	if bassLoudness > loudness - 3 { t.Fatalf("expected 30Hz tone to measure quieter (%f vs %f)", bassLoudness, loudness) }
+}
+
+// A [StdAudioStream] backed by a bytes.Reader.
+type testAudioStream struct {
+	*bytes.Reader
+}
+
+func (self testAudioStream) Length() int64 {
+	return self.Size()
+}
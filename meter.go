@@ -0,0 +1,69 @@
+package edau
+
+import "io"
+import "sync"
+
+// A Meter wraps an audio stream and passes it through unchanged, while
+// measuring the peak and RMS levels of each chunk of audio read. This
+// can be used to display level meters on the UI.
+//
+// The levels are measured with [MeasurePeak] and [MeasureRMS] on each
+// Read, so they correspond to the most recent buffer served to the
+// player, not to what's being heard right at that moment.
+type Meter struct {
+	mutex sync.Mutex
+	source io.Reader
+	peakLeft, peakRight float64
+	rmsLeft, rmsRight float64
+}
+
+// Creates a new [Meter].
+func NewMeter(source io.Reader) *Meter {
+	return &Meter{ source: source }
+}
+
+// Returns the underlying source.
+func (self *Meter) Source() io.Reader {
+	return self.source
+}
+
+// Returns the peak levels of the left and right channels for the most
+// recent Read, normalized to [0, 1].
+func (self *Meter) LastPeak() (float64, float64) {
+	self.mutex.Lock()
+	left, right := self.peakLeft, self.peakRight
+	self.mutex.Unlock()
+	return left, right
+}
+
+// Returns the RMS levels of the left and right channels for the most
+// recent Read, normalized to [0, 1].
+func (self *Meter) LastRMS() (float64, float64) {
+	self.mutex.Lock()
+	left, right := self.rmsLeft, self.rmsRight
+	self.mutex.Unlock()
+	return left, right
+}
+
+// Implements [io.Reader]. The returned read length will always be
+// multiple of 4, aligning to Ebitengine's sample size.
+func (self *Meter) Read(buffer []byte) (int, error) {
+	n, err := readFrames(self.source, buffer)
+	if n == 0 { return n, err }
+
+	peakLeft, peakRight := MeasurePeak(buffer[0 : n])
+	rmsLeft, rmsRight := MeasureRMS(buffer[0 : n])
+	self.mutex.Lock()
+	self.peakLeft, self.peakRight = peakLeft, peakRight
+	self.rmsLeft, self.rmsRight = rmsLeft, rmsRight
+	self.mutex.Unlock()
+	return n, err
+}
+
+// Implements [io.Seeker]. The last measured levels are preserved.
+//
+// If the underlying source doesn't implement [io.Seeker], [ErrNotSeekable]
+// is returned.
+func (self *Meter) Seek(offset int64, whence int) (int64, error) {
+	return seekSource(self.source, offset, whence)
+}
@@ -0,0 +1,156 @@
+package edau
+
+import "io"
+import "sync"
+import "time"
+
+// A Mixer plays multiple audio streams at the same time, summing them
+// together. Sources can be added at any time, either to start right away
+// with [Mixer.Add] or at a specific point of the mixer's timeline with
+// [Mixer.AddAt], which makes the mixer usable as a simple scheduler for
+// sounds that must be synchronized with the music, like in rhythm games.
+//
+// The mixer keeps its own clock, counted in frames and advanced on each
+// Read, so scheduled starts are sample-accurate regardless of the read
+// sizes. Sources are removed automatically once they reach [io.EOF], but
+// the mixer itself never ends: when there's nothing to play, silence is
+// returned instead. The sum is clipped if it goes out of range, so the
+// volume of the sources should leave some headroom.
+type Mixer struct {
+	mutex sync.Mutex
+	sampleRate int
+	clock int64 // in frames
+	nextID int
+	entries []mixerEntry
+	sum []float64
+	buffer []byte
+}
+
+type mixerEntry struct {
+	id int
+	source io.Reader
+	start int64 // in frames, on the mixer's clock
+}
+
+// Creates a new [Mixer] without sources. If the sample rate is 0,
+// [DefaultSampleRate] is used.
+func NewMixer(sampleRate int) *Mixer {
+	sampleRate = resolveSampleRate(sampleRate, "NewMixer")
+	return &Mixer{ sampleRate: sampleRate }
+}
+
+// Adds a source to be played starting from the next Read. Returns an
+// id that can be used with [Mixer.Remove].
+func (self *Mixer) Add(source io.Reader) int {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	return self.addAt(source, self.clock)
+}
+
+// Adds a source to be played starting at the given point of the mixer's
+// timeline, where 0 is the start of the first Read (see [Mixer.Elapsed]).
+// If that point has already passed, the source starts from the next Read
+// instead. Returns an id that can be used with [Mixer.Remove]. This method
+// will panic if the duration is negative.
+func (self *Mixer) AddAt(source io.Reader, at time.Duration) int {
+	if at < 0 { panic("AddAt duration can't be negative") }
+	start := SamplesForDuration(at, self.sampleRate)
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	if start < self.clock { start = self.clock }
+	return self.addAt(source, start)
+}
+
+func (self *Mixer) addAt(source io.Reader, start int64) int {
+	id := self.nextID
+	self.nextID += 1
+	self.entries = append(self.entries, mixerEntry{ id: id, source: source, start: start })
+	return id
+}
+
+// Removes the source with the given id, whether it had started playing or
+// not. Returns false if the source was not found, which can happen if it
+// had already ended.
+func (self *Mixer) Remove(id int) bool {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	for i, entry := range self.entries {
+		if entry.id != id { continue }
+		self.entries = append(self.entries[ : i], self.entries[i + 1 : ]...)
+		return true
+	}
+	return false
+}
+
+// Returns the number of sources that are playing or scheduled to play.
+func (self *Mixer) Count() int {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	return len(self.entries)
+}
+
+// Returns the position of the mixer's clock, which is the amount of
+// audio mixed so far. Notice that the audio that has been read is not
+// necessarily audible yet, as players have their own internal buffers.
+func (self *Mixer) Elapsed() time.Duration {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	return DurationForSamples(self.clock, self.sampleRate)
+}
+
+// Implements [io.Reader]. The returned read length will always be
+// multiple of 4, aligning to Ebitengine's sample size, and the mixer
+// never returns [io.EOF].
+//
+// If a source returns an error other than [io.EOF], the source is removed
+// and the error is returned after mixing the rest of the sources.
+func (self *Mixer) Read(buffer []byte) (int, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	frames := len(buffer)/4
+	if frames == 0 { return 0, nil }
+	if cap(self.sum) < frames*2 { self.sum = make([]float64, frames*2) }
+	if cap(self.buffer) < frames*4 { self.buffer = make([]byte, frames*4) }
+	sum := self.sum[0 : frames*2]
+	for i := range sum { sum[i] = 0 }
+
+	var readErr error
+	entries := self.entries[ : 0]
+	for _, entry := range self.entries {
+		// skip sources that haven't started yet
+		offset := entry.start - self.clock
+		if offset >= int64(frames) {
+			entries = append(entries, entry)
+			continue
+		}
+		if offset < 0 { offset = 0 }
+
+		// read and add the source
+		sourceBuffer := self.buffer[0 : (int64(frames) - offset)*4]
+		n, err := io.ReadFull(entry.source, sourceBuffer)
+		for i := 0; i + 4 <= n; i += 4 {
+			left, right := GetSampleAsF64(sourceBuffer[i : ])
+			index := (int(offset) + i/4)*2
+			sum[index + 0] += left
+			sum[index + 1] += right
+		}
+
+		// keep the source unless it ended or failed
+		if err == nil {
+			entries = append(entries, entry)
+		} else if err != io.EOF && err != io.ErrUnexpectedEOF && readErr == nil {
+			readErr = err
+		}
+	}
+	for i := len(entries); i < len(self.entries); i++ {
+		self.entries[i] = mixerEntry{} // release removed sources
+	}
+	self.entries = entries
+
+	for i := 0; i < frames; i++ {
+		StoreNormF64SampleAsL16(buffer[i*4 : ], sum[i*2 + 0], sum[i*2 + 1])
+	}
+	self.clock += int64(frames)
+	return frames*4, readErr
+}
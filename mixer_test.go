@@ -0,0 +1,52 @@
+package edau
+
+import "io"
+import "math"
+import "time"
+import "testing"
+
+func TestMixerAddAt(t *testing.T) {
+	const sampleRate = 1000
+	click := func(frames int) []byte {
+		data := make([]byte, frames*4)
+		for i := 0; i < len(data); i += 4 { StoreNormF64SampleAsL16(data[i : ], 0.25, -0.25) }
+		return data
+	}
+
+	mixer := NewMixer(sampleRate)
+	mixer.AddAt(NewPCMBuffer(click(10)), 100*time.Millisecond) // frames [100, 110)
+	mixer.AddAt(NewPCMBuffer(click(10)), 105*time.Millisecond) // frames [105, 115)
+	removed := mixer.AddAt(NewPCMBuffer(click(10)), 50*time.Millisecond)
+	if !mixer.Remove(removed) { t.Fatal("expected Remove to find the source") }
+	if mixer.Remove(removed) { t.Fatal("expected Remove to fail on a removed source") }
+	if mixer.Count() != 2 { t.Fatalf("expected 2 sources, got %d", mixer.Count()) }
+
+	// read in odd sizes so the starts fall in the middle of reads
+	output := make([]byte, 200*4)
+	for i := 0; i < len(output); {
+		size := 4*7
+		if size > len(output) - i { size = len(output) - i }
+		n, err := mixer.Read(output[i : i + size])
+		if err != nil { t.Fatal(err) }
+		i += n
+	}
+	if mixer.Elapsed() != 200*time.Millisecond { t.Fatalf("unexpected elapsed time %s", mixer.Elapsed()) }
+	if mixer.Count() != 0 { t.Fatalf("expected the sources to be removed after ending, got %d", mixer.Count()) }
+
+	for frame := 0; frame < 200; frame++ {
+		expected := 0.0
+		if frame >= 100 && frame < 110 { expected += 0.25 }
+		if frame >= 105 && frame < 115 { expected += 0.25 }
+		left, right := GetSampleAsF64(output[frame*4 : ])
+		if math.Abs(left - expected) > 0.001 || math.Abs(right + expected) > 0.001 {
+			t.Fatalf("frame %d: expected %f, got (%f, %f)", frame, expected, left, right)
+		}
+	}
+
+	// sources scheduled in the past start on the next read
+	mixer.AddAt(NewPCMBuffer(click(5)), 0)
+	output, err := io.ReadAll(io.LimitReader(mixer, 10*4))
+	if err != nil { t.Fatal(err) }
+	if left, _ := GetSampleAsF64(output); left < 0.249 { t.Fatalf("expected immediate start, got %f", left) }
+	if left, _ := GetSampleAsF64(output[5*4 : ]); left != 0 { t.Fatalf("expected silence after the source, got %f", left) }
+}
@@ -0,0 +1,40 @@
+package edau
+
+import "io"
+
+// A MonoDownmix wraps a stereo audio stream and averages both channels,
+// so the left and right channels of the output carry the same mono signal.
+// The output is still in Ebitengine's default 2-channel L16 format.
+type MonoDownmix struct {
+	source io.Reader
+}
+
+// Creates a new [MonoDownmix].
+func NewMonoDownmix(source io.Reader) *MonoDownmix {
+	return &MonoDownmix{ source: source }
+}
+
+// Returns the underlying source.
+func (self *MonoDownmix) Source() io.Reader {
+	return self.source
+}
+
+// Implements [io.Reader]. The returned read length will always be
+// multiple of 4, aligning to Ebitengine's sample size.
+func (self *MonoDownmix) Read(buffer []byte) (int, error) {
+	n, err := readFrames(self.source, buffer)
+	for i := 0; i < n; i += 4 {
+		left, right := GetSampleAsI16(buffer[i : ])
+		mono := int16((int32(left) + int32(right))/2)
+		StoreL16Sample(buffer[i : ], mono, mono)
+	}
+	return n, err
+}
+
+// Implements [io.Seeker].
+//
+// If the underlying source doesn't implement [io.Seeker], [ErrNotSeekable]
+// is returned.
+func (self *MonoDownmix) Seek(offset int64, whence int) (int64, error) {
+	return seekSource(self.source, offset, whence)
+}
@@ -0,0 +1,85 @@
+package edau
+
+import "io"
+import "errors"
+
+// A MultiLooper is a [Looper] over the concatenation of multiple streams,
+// which allows looping seamlessly across the boundaries of audio that has
+// been split into multiple files. The loop points, positions and seeks are
+// all expressed in the concatenated byte space, and they are internally
+// mapped to the right underlying stream.
+//
+// All the [Looper] methods are available on the MultiLooper.
+type MultiLooper struct {
+	*Looper
+}
+
+// Creates a new [MultiLooper]. At least one stream must be given, and the
+// loop points must follow the same rules as in [NewLooper]. This method
+// will panic otherwise.
+func NewMultiLooper(streams []StdAudioStream, loopStart int64, loopEnd int64) *MultiLooper {
+	if len(streams) == 0 { panic("NewMultiLooper requires at least one stream") }
+	return &MultiLooper{ NewLooper(newConcatStream(streams), loopStart, loopEnd) }
+}
+
+// A [StdAudioStream] concatenating multiple streams.
+type concatStream struct {
+	streams []StdAudioStream
+	offsets []int64 // start offset of each stream in the concatenated space
+	length int64
+	index int // current stream
+	position int64
+}
+
+func newConcatStream(streams []StdAudioStream) *concatStream {
+	offsets := make([]int64, len(streams))
+	var length int64
+	for i, stream := range streams {
+		offsets[i] = length
+		length += stream.Length()
+	}
+	return &concatStream {
+		streams: streams,
+		offsets: offsets,
+		length: length,
+	}
+}
+
+func (self *concatStream) Read(buffer []byte) (int, error) {
+	for {
+		n, err := self.streams[self.index].Read(buffer)
+		self.position += int64(n)
+		if err != io.EOF { return n, err }
+
+		// move to the next stream if the current one is over
+		if self.index == len(self.streams) - 1 { return n, io.EOF }
+		self.index += 1
+		_, err = self.streams[self.index].Seek(0, io.SeekStart)
+		if err != nil { return n, err }
+		if n > 0 { return n, nil }
+	}
+}
+
+func (self *concatStream) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart  : // nothing to adjust
+	case io.SeekCurrent: offset += self.position
+	case io.SeekEnd    : offset += self.length
+	default:
+		return self.position, errors.New("invalid whence")
+	}
+	if offset < 0 { return self.position, errors.New("negative position") }
+
+	// find the stream containing the offset. offsets beyond the
+	// end are placed on the last stream
+	index := len(self.offsets) - 1
+	for index > 0 && self.offsets[index] > offset { index -= 1 }
+	_, err := self.streams[index].Seek(offset - self.offsets[index], io.SeekStart)
+	if err != nil { return self.position, err }
+	self.index, self.position = index, offset
+	return offset, nil
+}
+
+func (self *concatStream) Length() int64 {
+	return self.length
+}
@@ -0,0 +1,49 @@
+package edau
+
+import "io"
+import "math"
+import "bytes"
+
+// Reads the whole stream and returns a copy of it in memory, scaled so its
+// peak (measured with [MeasurePeak] across both channels) matches the target
+// peak, which must be in (0, 1]. This method will panic otherwise. If the
+// stream is silent, the copy is left unchanged.
+//
+// This is the offline counterpart of [AGC], intended for preprocessing
+// assets at load time. The stream is read twice from the beginning, first
+// to find the peak and then to copy the audio, so the gain is constant for
+// the whole stream and no audio is clipped. When done, the stream is left
+// at position 0. See [NormalizeBuffer] if the audio is already in memory.
+func NormalizeStream(stream StdAudioStream, targetPeak float64) (*bytes.Reader, error) {
+	if targetPeak <= 0 || targetPeak > 1.0 { panic("targetPeak must be in (0, 1]") }
+
+	// first pass: find the peak
+	_, err := stream.Seek(0, io.SeekStart)
+	if err != nil { return nil, err }
+	var peak float64
+	buffer := make([]byte, 16384)
+	for {
+		n, err := readFrames(stream, buffer)
+		peak = math.Max(peak, math.Max(MeasurePeak(buffer[0 : n])))
+		if err == io.EOF { break }
+		if err != nil { return nil, err }
+	}
+
+	// second pass: copy the audio and apply the gain
+	_, err = stream.Seek(0, io.SeekStart)
+	if err != nil { return nil, err }
+	var data []byte
+	if length, known := StreamLength(stream); known {
+		data = make([]byte, 0, length)
+	}
+	output := bytes.NewBuffer(data)
+	_, err = io.Copy(output, stream)
+	if err != nil { return nil, err }
+	data = output.Bytes()
+	data = data[0 : len(data) - (len(data) & 0b11)]
+	if peak > 0 { ApplyGain(data, targetPeak/peak) }
+
+	_, err = stream.Seek(0, io.SeekStart)
+	if err != nil { return nil, err }
+	return bytes.NewReader(data), nil
+}
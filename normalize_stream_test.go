@@ -0,0 +1,36 @@
+package edau
+
+import "io"
+import "math"
+import "bytes"
+import "testing"
+
+func TestNormalizeStream(t *testing.T) {
+	input := testSineL16(440, 0.25, testSampleRate)
+	stream := NewPCMBuffer(input)
+	_, err := stream.Seek(4000, io.SeekStart)
+	if err != nil { t.Fatal(err) }
+
+	normalized, err := NormalizeStream(stream, 0.5)
+	if err != nil { t.Fatal(err) }
+	if position, _ := stream.Seek(0, io.SeekCurrent); position != 0 {
+		t.Fatalf("expected the source at position 0, got %d", position)
+	}
+	if normalized.Size() != int64(len(input)) {
+		t.Fatalf("expected %d bytes, got %d", len(input), normalized.Size())
+	}
+	output, err := io.ReadAll(normalized)
+	if err != nil { t.Fatal(err) }
+	left, right := MeasurePeak(output)
+	if math.Abs(left - 0.5) > 0.001 || math.Abs(right - 0.5) > 0.001 {
+		t.Fatalf("expected peaks at 0.5, got %f, %f", left, right)
+	}
+
+	// silence is left untouched
+	silence := make([]byte, 4096)
+	normalized, err = NormalizeStream(NewPCMBuffer(silence), 0.5)
+	if err != nil { t.Fatal(err) }
+	output, err = io.ReadAll(normalized)
+	if err != nil { t.Fatal(err) }
+	if !bytes.Equal(output, silence) { t.Fatal("expected silence to be left untouched") }
+}
@@ -0,0 +1,19 @@
+module github.com/tinne26/edau/opus
+
+go 1.24.0
+
+require (
+	github.com/pion/opus v0.1.0
+	github.com/tinne26/edau v0.0.0
+)
+
+require (
+	github.com/hajimehoshi/ebiten/v2 v2.3.7 // indirect
+	github.com/hajimehoshi/go-mp3 v0.3.3 // indirect
+	github.com/hajimehoshi/oto/v2 v2.1.0 // indirect
+	github.com/jfreymuth/oggvorbis v1.0.3 // indirect
+	github.com/jfreymuth/vorbis v1.0.2 // indirect
+	golang.org/x/sys v0.0.0-20220408201424-a24fb2fb8a0f // indirect
+)
+
+replace github.com/tinne26/edau => ../
@@ -0,0 +1,73 @@
+// Package opus provides an Opus decoder for edau, based on the pure Go
+// decoder from github.com/pion/opus. Importing the package registers the
+// decoder with [edau.RegisterOpusDecoder], so .opus files can be loaded
+// with [edau.LoadAudioFileAsStream] and similar functions:
+//    import _ "github.com/tinne26/edau/opus"
+//
+// This is a separate module because the decoder requires Go 1.24 or newer,
+// while edau itself supports older Go versions.
+package opus
+
+import "io"
+import "math"
+import "bytes"
+import "errors"
+
+import "github.com/tinne26/edau"
+import "github.com/pion/opus"
+import "github.com/pion/opus/pkg/oggreader"
+
+const sampleRate = 48000 // opus is always decoded at 48kHz
+const maxPacketFrames = 5760 // 120ms at 48kHz
+
+func init() {
+	edau.RegisterOpusDecoder(Decode)
+}
+
+// Decodes a whole Ogg Opus stream into memory, as a L16 little-endian stream
+// with two channels at 48kHz (Opus' native sample rate). Mono files are
+// expanded to both channels. Only mono and stereo files are supported.
+func Decode(reader io.Reader) (edau.StdAudioStream, error) {
+	ogg, header, err := oggreader.NewWith(reader)
+	if err != nil { return nil, err }
+	if header.Channels < 1 || header.Channels > 2 {
+		return nil, errors.New("only mono and stereo opus files are supported")
+	}
+	decoder, err := opus.NewDecoderWithOutput(sampleRate, 2)
+	if err != nil { return nil, err }
+
+	// decode all the audio packets
+	var data []byte
+	var lastGranule uint64
+	pcm := make([]int16, maxPacketFrames*2)
+	for {
+		packet, pageHeader, err := ogg.ParseNextPacket()
+		if err == io.EOF { break }
+		if err != nil { return nil, err }
+		if bytes.HasPrefix(packet, []byte("OpusTags")) { continue }
+
+		frames, err := decoder.DecodeToInt16(packet, pcm)
+		if err != nil { return nil, err }
+		for _, sample := range pcm[0 : frames*2] {
+			data = append(data, byte(sample), byte(uint16(sample) >> 8))
+		}
+		lastGranule = pageHeader.GranulePosition
+	}
+
+	// trim the encoder delay at the start and the padding at the end,
+	// as indicated by the pre-skip and the final granule position
+	preSkip := int(header.PreSkip)*4
+	if preSkip > len(data) { preSkip = len(data) }
+	data = data[preSkip : ]
+	if lastGranule > uint64(header.PreSkip) {
+		end := (lastGranule - uint64(header.PreSkip))*4
+		if end < uint64(len(data)) { data = data[0 : end] }
+	}
+
+	// apply the output gain, stored in Q7.8 dB
+	if header.OutputGain != 0 {
+		gainDb := float64(int16(header.OutputGain))/256.0
+		edau.ApplyGain(data, math.Pow(10, gainDb/20.0))
+	}
+	return edau.NewPCMBuffer(data), nil
+}
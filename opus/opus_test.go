@@ -0,0 +1,39 @@
+package opus
+
+import "io"
+import "bytes"
+import "testing"
+
+import "github.com/tinne26/edau"
+
+// A tiny Ogg Opus file with a single 20ms mono packet, a pre-skip of 312
+// samples and a final granule position of 591, taken from the test data
+// of github.com/pion/opus (MIT license).
+var testOpusFile = []byte{
+	0x4f, 0x67, 0x67, 0x53, 0x00, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x79, 0x62,
+	0xef, 0xee, 0x00, 0x00, 0x00, 0x00, 0xd7, 0x16, 0x5d, 0x6c, 0x01, 0x13, 0x4f, 0x70, 0x75, 0x73,
+	0x48, 0x65, 0x61, 0x64, 0x01, 0x01, 0x38, 0x01, 0x80, 0xbb, 0x00, 0x00, 0x00, 0x00, 0x00, 0x4f,
+	0x67, 0x67, 0x53, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x79, 0x62, 0xef,
+	0xee, 0x01, 0x00, 0x00, 0x00, 0x6a, 0xfd, 0x4f, 0x1a, 0x01, 0x3e, 0x4f, 0x70, 0x75, 0x73, 0x54,
+	0x61, 0x67, 0x73, 0x0d, 0x00, 0x00, 0x00, 0x4c, 0x61, 0x76, 0x66, 0x35, 0x39, 0x2e, 0x31, 0x36,
+	0x2e, 0x31, 0x30, 0x30, 0x01, 0x00, 0x00, 0x00, 0x1d, 0x00, 0x00, 0x00, 0x65, 0x6e, 0x63, 0x6f,
+	0x64, 0x65, 0x72, 0x3d, 0x4c, 0x61, 0x76, 0x63, 0x35, 0x39, 0x2e, 0x31, 0x38, 0x2e, 0x31, 0x30,
+	0x30, 0x20, 0x6c, 0x69, 0x62, 0x6f, 0x70, 0x75, 0x73, 0x4f, 0x67, 0x67, 0x53, 0x00, 0x04, 0x4f,
+	0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x79, 0x62, 0xef, 0xee, 0x02, 0x00, 0x00, 0x00, 0x6e,
+	0x45, 0x59, 0x46, 0x01, 0x0f, 0x48, 0x83, 0xca, 0xde, 0x8a, 0xe5, 0x67, 0xd5, 0x1c, 0xac, 0xa2,
+	0x54, 0xfa, 0xff, 0xbf,
+}
+
+func TestDecodeOpus(t *testing.T) {
+	// 591 - 312 = 279 frames at 48kHz
+	stream, err := Decode(bytes.NewReader(testOpusFile))
+	if err != nil { t.Fatal(err) }
+	if stream.Length() != 279*4 { t.Fatalf("expected %d bytes, got %d", 279*4, stream.Length()) }
+	data, err := io.ReadAll(stream)
+	if err != nil { t.Fatal(err) }
+	if len(data) != 279*4 { t.Fatalf("expected %d bytes, got %d", 279*4, len(data)) }
+	for i := 0; i < len(data); i += 4 {
+		left, right := edau.GetSampleAsI16(data[i : ])
+		if left != right { t.Fatalf("expected mono audio on both channels at frame %d", i/4) }
+	}
+}
@@ -0,0 +1,114 @@
+package edau
+
+import "io"
+import "time"
+import "errors"
+
+// A PaddedStream wraps an audio stream and adds silence before and after
+// its content. This is useful to offset a track in time, e.g. to align it
+// with other tracks, without having to use a full mixer. Positions, seeks
+// and [PaddedStream.Length] all take the padding into account.
+//
+// The underlying stream shouldn't be used directly while the padded stream
+// is in use, as the padded stream relies on the stream's position.
+type PaddedStream struct {
+	source StdAudioStream
+	leading int64 // in bytes
+	trailing int64 // in bytes
+	position int64
+	needsSeek bool
+}
+
+// Creates a new [PaddedStream] with the given amounts of silence before and
+// after the source's content. Durations are rounded down to whole samples.
+// The durations can't be negative. This method will panic otherwise. If
+// the sample rate is 0, [DefaultSampleRate] is used.
+func NewPaddedStream(source StdAudioStream, leadingSilence, trailingSilence time.Duration, sampleRate int) *PaddedStream {
+	sampleRate = resolveSampleRate(sampleRate, "NewPaddedStream")
+	if leadingSilence  < 0 { panic("NewPaddedStream leadingSilence can't be negative") }
+	if trailingSilence < 0 { panic("NewPaddedStream trailingSilence can't be negative") }
+	return &PaddedStream {
+		source: source,
+		leading: BytesForDuration(leadingSilence, sampleRate),
+		trailing: BytesForDuration(trailingSilence, sampleRate),
+		needsSeek: true,
+	}
+}
+
+// Returns the underlying source.
+func (self *PaddedStream) Source() StdAudioStream {
+	return self.source
+}
+
+// Implements [io.Reader].
+func (self *PaddedStream) Read(buffer []byte) (int, error) {
+	contentEnd := self.leading + self.source.Length()
+	length := contentEnd + self.trailing
+	if self.position >= length { return 0, io.EOF }
+
+	// leading silence
+	if self.position < self.leading {
+		n := readSilence(buffer, self.leading - self.position)
+		self.position += int64(n)
+		return n, nil
+	}
+
+	// source content
+	if self.position < contentEnd {
+		if self.needsSeek {
+			_, err := self.source.Seek(self.position - self.leading, io.SeekStart)
+			if err != nil { return 0, err }
+			self.needsSeek = false
+		}
+		if int64(len(buffer)) > contentEnd - self.position {
+			buffer = buffer[0 : contentEnd - self.position]
+		}
+		n, err := self.source.Read(buffer)
+		self.position += int64(n)
+		if err == io.EOF {
+			// the source ended earlier than expected, skip to the trailing silence
+			self.position = contentEnd
+			err = nil
+		}
+		return n, err
+	}
+
+	// trailing silence
+	n := readSilence(buffer, length - self.position)
+	self.position += int64(n)
+	if self.position >= length { return n, io.EOF }
+	return n, nil
+}
+
+// Implements [io.Seeker]. Offsets include the padding.
+func (self *PaddedStream) Seek(offset int64, whence int) (int64, error) {
+	var position int64
+	switch whence {
+	case io.SeekStart:
+		position = offset
+	case io.SeekCurrent:
+		position = self.position + offset
+	case io.SeekEnd:
+		position = self.Length() + offset
+	default:
+		return self.position, errors.New("PaddedStream.Seek: invalid whence")
+	}
+	if position < 0 { return self.position, errors.New("PaddedStream.Seek: negative position") }
+
+	// seek the source right away if the position falls within its
+	// content, so errors are reported here instead of on Read
+	if position >= self.leading && position < self.leading + self.source.Length() {
+		_, err := self.source.Seek(position - self.leading, io.SeekStart)
+		if err != nil { return self.position, err }
+		self.needsSeek = false
+	} else {
+		self.needsSeek = true
+	}
+	self.position = position
+	return position, nil
+}
+
+// Returns the length of the stream, in bytes, including the padding.
+func (self *PaddedStream) Length() int64 {
+	return self.leading + self.source.Length() + self.trailing
+}
@@ -0,0 +1,34 @@
+package edau
+
+import "bytes"
+
+// A PCMBuffer wraps a byte slice with raw audio data and implements
+// [StdAudioStream]. It's the simplest way to use in-memory audio with
+// the wrappers that require a length, like [Looper].
+//
+// Unlike [bytes.Reader.Len], which returns the number of unread bytes,
+// [PCMBuffer.Length] always returns the total size of the data, no
+// matter the current position.
+type PCMBuffer struct {
+	*bytes.Reader
+	data []byte
+}
+
+// Creates a new [PCMBuffer] for the given data. The data is not copied,
+// so it shouldn't be modified while the buffer is in use.
+func NewPCMBuffer(data []byte) *PCMBuffer {
+	return &PCMBuffer {
+		Reader: bytes.NewReader(data),
+		data: data,
+	}
+}
+
+// Returns the total length of the data, in bytes.
+func (self *PCMBuffer) Length() int64 {
+	return int64(len(self.data))
+}
+
+// Returns the underlying data. The returned slice is not a copy.
+func (self *PCMBuffer) Bytes() []byte {
+	return self.data
+}
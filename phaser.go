@@ -0,0 +1,157 @@
+package edau
+
+import "io"
+import "math"
+import "sync"
+
+const phaserMinFreq = 200.0 // Hz, lowest point of the sweep
+const phaserMaxFreq = 4000.0 // Hz, highest point of the sweep at full depth
+const phaserQ = 0.7
+const phaserMaxStages = 12
+const phaserUpdateFrames = 32 // the allpass coefficients are updated every 32 frames
+
+// A Phaser wraps an audio stream and passes it through a chain of allpass
+// filters whose center frequency is swept by a low frequency oscillator
+// (LFO). When mixed with the dry signal, the phase shifts introduced by the
+// allpasses create notches in the spectrum, and their movement results in
+// the characteristic "whooshing" sound of the effect.
+//
+// Each stage adds a notch, and the notches are deepest with a mix of 0.5.
+// The sweep goes from 200Hz up to 4000Hz at full depth, and it's done in
+// the logarithmic domain, so it sounds even across the whole range.
+type Phaser struct {
+	mutex sync.Mutex
+	source io.Reader
+	sampleRate int
+	depth float64
+	mix float64
+	lfo *LFO
+	stages []biquadFilter
+	framesToUpdate int
+}
+
+// Creates a new [Phaser]. The number of stages must be in [1, 12], the rate
+// in [0, sampleRate/2), and the depth and mix in [0, 1]. Typical values are
+// around 4 stages, 0.5Hz, 0.7 depth and 0.5 mix. The sample rate must be
+// high enough for the sweep range (at least 8001Hz). This method will panic
+// if any of the values are invalid.
+// If the sample rate is 0, [DefaultSampleRate] is used.
+func NewPhaser(source io.Reader, stages int, rateHz, depth, mix float64, sampleRate int) *Phaser {
+	sampleRate = resolveSampleRate(sampleRate, "NewPhaser")
+	if stages < 1 || stages > phaserMaxStages { panic("NewPhaser stages must be in [1, 12]") }
+	assertFilterFreqValidity(phaserMaxFreq, sampleRate)
+	assertUnitRangeParam("depth", depth)
+	assertMixValidity(mix)
+	return &Phaser {
+		source: source,
+		sampleRate: sampleRate,
+		lfo: NewLFO(LFOSine, rateHz, sampleRate),
+		depth: depth,
+		mix: mix,
+		stages: make([]biquadFilter, stages),
+	}
+}
+
+// Returns the underlying source.
+func (self *Phaser) Source() io.Reader {
+	return self.source
+}
+
+// Returns the currently configured LFO rate, in Hz.
+func (self *Phaser) Rate() float64 {
+	return self.lfo.Rate()
+}
+
+// Sets the LFO rate, in Hz. This method will panic if the value
+// is negative or above the Nyquist frequency.
+func (self *Phaser) SetRate(rateHz float64) {
+	self.lfo.SetRate(rateHz)
+}
+
+// Returns the currently configured depth.
+func (self *Phaser) Depth() float64 {
+	self.mutex.Lock()
+	depth := self.depth
+	self.mutex.Unlock()
+	return depth
+}
+
+// Sets the depth, which must be in [0, 1]. With a depth of 0, the
+// notches stay at their lowest position. This method will panic if
+// the value is invalid.
+func (self *Phaser) SetDepth(depth float64) {
+	assertUnitRangeParam("depth", depth)
+	self.mutex.Lock()
+	self.depth = depth
+	self.mutex.Unlock()
+}
+
+// Returns the currently configured mix factor.
+func (self *Phaser) Mix() float64 {
+	self.mutex.Lock()
+	mix := self.mix
+	self.mutex.Unlock()
+	return mix
+}
+
+// Sets the mix factor, which must be in [0, 1]. 0 is fully dry and 1
+// fully wet. This method will panic if the value is invalid.
+func (self *Phaser) SetMix(mix float64) {
+	assertMixValidity(mix)
+	self.mutex.Lock()
+	self.mix = mix
+	self.mutex.Unlock()
+}
+
+// Implements [io.Reader]. The returned read length will always be
+// multiple of 4, aligning to Ebitengine's sample size.
+func (self *Phaser) Read(buffer []byte) (int, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	n, err := readFrames(self.source, buffer)
+	for i := 0; i < n; i += 4 {
+		if self.framesToUpdate <= 0 { self.updateCoeffs() }
+		self.framesToUpdate -= 1
+
+		left, right := GetSampleAsF64(buffer[i : ])
+		wetLeft, wetRight := left, right
+		for s := range self.stages {
+			stage := &self.stages[s]
+			wetLeft  = stage.left.Next(&stage.coeffs, wetLeft)
+			wetRight = stage.right.Next(&stage.coeffs, wetRight)
+		}
+		left  = left*(1.0 - self.mix)  + wetLeft*self.mix
+		right = right*(1.0 - self.mix) + wetRight*self.mix
+		StoreNormF64SampleAsL16(buffer[i : ], left, right)
+
+		self.lfo.Advance()
+	}
+	return n, err
+}
+
+// Implements [io.Seeker]. The filter states are reset after seeking,
+// but the LFO phase is not.
+//
+// If the underlying source doesn't implement [io.Seeker], [ErrNotSeekable]
+// is returned.
+func (self *Phaser) Seek(offset int64, whence int) (int64, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	position, err := seekSource(self.source, offset, whence)
+	for i := range self.stages {
+		self.stages[i].Reset()
+	}
+	return position, err
+}
+
+// Updates the allpass coefficients for the current LFO phase.
+func (self *Phaser) updateCoeffs() {
+	lfo := 0.5 + 0.5*self.lfo.At(0)
+	freq := phaserMinFreq*math.Pow(phaserMaxFreq/phaserMinFreq, lfo*self.depth)
+	coeffs := newAllPassCoeffs(freq, phaserQ, self.sampleRate)
+	for i := range self.stages {
+		self.stages[i].coeffs = coeffs
+	}
+	self.framesToUpdate = phaserUpdateFrames
+}
@@ -0,0 +1,101 @@
+package edau
+
+import "math"
+
+// Frequency range considered by [EstimatePitch].
+const (
+	pitchMinFreq = 50.0
+	pitchMaxFreq = 2000.0
+)
+
+// Returns the estimated fundamental frequency of the left and right channels
+// for the given L16, 2 channel, little-endian buffer, in Hz. If a channel is
+// silent or doesn't have a clear pitch, 0 is returned for it. The sampleRate
+// must be strictly positive. This function will panic otherwise.
+//
+// The estimation uses the normalized autocorrelation of the signal, and is
+// reliable for monophonic sounds with fundamentals between 50Hz and 2000Hz
+// (roughly G1 to B6), which covers voices and most instruments. The buffer
+// must contain at least two periods of the lowest pitch to be detected, so
+// ~40ms of audio are needed to go down to 50Hz. Shorter buffers raise the
+// lower limit of the range accordingly. Chords, noise or heavily distorted
+// sounds will give unreliable results.
+//
+// The cost grows with the buffer size times the number of candidate periods,
+// so for real-time uses like tuners, keep buffers around 2048 samples.
+func EstimatePitch(buffer []byte, sampleRate int) (float64, float64) {
+	if sampleRate <= 0 { panic("EstimatePitch sampleRate must be strictly positive") }
+	numFrames := len(buffer)/4
+	left, right := make([]float64, numFrames), make([]float64, numFrames)
+	var frame [2]float64
+	for i := 0; i < numFrames; i++ {
+		ReadNormalizedFrameInto(buffer[i*4 : ], &frame)
+		left[i], right[i] = frame[0], frame[1]
+	}
+	return estimateChannelPitch(left, sampleRate), estimateChannelPitch(right, sampleRate)
+}
+
+// Returns the zero-crossing rate of the left and right channels for the
+// given L16, 2 channel, little-endian buffer, in crossings per second. For
+// a pure tone, this is twice its frequency, but noise and harmonics add
+// extra crossings, so the rate is better suited to tell noisy sounds apart
+// from tonal ones than to measure pitch. See [EstimatePitch] instead. The
+// sampleRate must be strictly positive. This function will panic otherwise.
+func ZeroCrossingRate(buffer []byte, sampleRate int) (float64, float64) {
+	if sampleRate <= 0 { panic("ZeroCrossingRate sampleRate must be strictly positive") }
+	numFrames := len(buffer)/4
+	if numFrames < 2 { return 0, 0 }
+	var crossingsLeft, crossingsRight int
+	prevLeft, prevRight := GetSampleAsI16(buffer)
+	for i := 4; i < numFrames*4; i += 4 {
+		left, right := GetSampleAsI16(buffer[i : ])
+		if (left < 0) != (prevLeft < 0) { crossingsLeft += 1 }
+		if (right < 0) != (prevRight < 0) { crossingsRight += 1 }
+		prevLeft, prevRight = left, right
+	}
+	seconds := float64(numFrames - 1)/float64(sampleRate)
+	return float64(crossingsLeft)/seconds, float64(crossingsRight)/seconds
+}
+
+func estimateChannelPitch(samples []float64, sampleRate int) float64 {
+	const silenceLevel = 0.0001 // ~-80dB
+	const minCorrelation = 0.5
+
+	// determine the lag range
+	minLag := int(float64(sampleRate)/pitchMaxFreq)
+	if minLag < 2 { minLag = 2 }
+	maxLag := int(math.Ceil(float64(sampleRate)/pitchMinFreq))
+	if maxLag > len(samples)/2 { maxLag = len(samples)/2 }
+	if maxLag <= minLag { return 0 }
+
+	// skip silence
+	var energy float64
+	for _, sample := range samples { energy += sample*sample }
+	if math.Sqrt(energy/float64(len(samples))) < silenceLevel { return 0 }
+
+	// compute the autocorrelation for each lag, with one
+	// extra lag at each side for the local maxima checks
+	correlations := make([]float64, maxLag - minLag + 3)
+	bestCorrelation := math.Inf(-1)
+	for i := range correlations {
+		lag := minLag - 1 + i
+		correlations[i] = pearsonCorrelation(samples[0 : len(samples) - lag], samples[lag : ])
+		bestCorrelation = math.Max(bestCorrelation, correlations[i])
+	}
+	if bestCorrelation < minCorrelation { return 0 }
+
+	// pick the first local maximum close enough to the best one. the
+	// best correlation is often found at multiples of the period, so
+	// picking it directly would result in octave errors
+	for i := 1; i < len(correlations) - 1; i++ {
+		prev, curr, next := correlations[i - 1], correlations[i], correlations[i + 1]
+		if curr < prev || curr < next || curr < bestCorrelation*0.9 { continue }
+
+		// refine the lag with parabolic interpolation
+		lag := float64(minLag - 1 + i)
+		curvature := prev - 2*curr + next
+		if curvature < 0 { lag += 0.5*(prev - next)/curvature }
+		return float64(sampleRate)/lag
+	}
+	return 0
+}
@@ -0,0 +1,36 @@
+package edau
+
+import "math"
+import "testing"
+
+func TestEstimatePitch(t *testing.T) {
+	for _, freq := range []float64{ 55, 82.41, 220, 440, 1000, 1975.5 } {
+		buffer := testSineL16(freq, 0.5, testSampleRate)[0 : 4096*4]
+		left, right := EstimatePitch(buffer, testSampleRate)
+		if math.Abs(left - freq) > freq*0.01 || math.Abs(right - freq) > freq*0.01 {
+			t.Fatalf("expected pitch %.2fHz, got %.2fHz / %.2fHz", freq, left, right)
+		}
+	}
+
+	// harmonics shouldn't cause octave errors
+	buffer := testSineL16(220, 0.4, testSampleRate)[0 : 4096*4]
+	overtone := testSineL16(440, 0.3, testSampleRate)
+	for i := 0; i < len(buffer); i += 4 {
+		left, right := GetSampleAsF64(buffer[i : ])
+		overLeft, overRight := GetSampleAsF64(overtone[i : ])
+		StoreNormF64SampleAsL16(buffer[i : ], left + overLeft, right + overRight)
+	}
+	left, _ := EstimatePitch(buffer, testSampleRate)
+	if math.Abs(left - 220) > 220*0.01 { t.Fatalf("expected pitch 220Hz with overtone, got %.2fHz", left) }
+
+	// silence has no pitch
+	left, right := EstimatePitch(make([]byte, 4096*4), testSampleRate)
+	if left != 0 || right != 0 { t.Fatalf("expected no pitch for silence, got %.2fHz / %.2fHz", left, right) }
+}
+
+func TestZeroCrossingRate(t *testing.T) {
+	left, right := ZeroCrossingRate(testSineL16(440, 0.5, testSampleRate), testSampleRate)
+	if math.Abs(left - 880) > 2 || math.Abs(right - 880) > 2 {
+		t.Fatalf("expected ~880 crossings per second, got %.2f / %.2f", left, right)
+	}
+}
@@ -0,0 +1,137 @@
+package edau
+
+import "io"
+import "sync"
+
+const prefetcherMaxChunkSize = 8192
+
+// A Prefetcher wraps an audio stream and reads ahead from it on a background
+// goroutine, storing the data in a ring buffer. Reads on the prefetcher then
+// only need to copy data from the buffer, which prevents decoding hitches
+// from causing dropouts on the audio goroutine.
+//
+// The background goroutine is started on creation and keeps running until
+// [Prefetcher.Close] is called. Closing the prefetcher doesn't close the
+// underlying source.
+type Prefetcher struct {
+	mutex sync.Mutex
+	cond *sync.Cond // signals changes in the buffer or closing
+	sourceMutex sync.Mutex // serializes accesses to the source
+	source io.Reader
+	buffer *RingBuffer
+	err error // sticky error from the source, including io.EOF
+	closed bool
+}
+
+// Creates a new [Prefetcher] and starts its background goroutine. The
+// bufferSize, in bytes, determines how far ahead the source is read. For
+// example, 44100*4 bytes is one second of audio at 44.1kHz. The bufferSize
+// must be at least 4. This method will panic otherwise.
+func NewPrefetcher(source io.Reader, bufferSize int) *Prefetcher {
+	if bufferSize < 4 { panic("NewPrefetcher bufferSize must be at least 4") }
+	prefetcher := &Prefetcher {
+		source: source,
+		buffer: NewRingBuffer(bufferSize),
+	}
+	prefetcher.cond = sync.NewCond(&prefetcher.mutex)
+	go prefetcher.run()
+	return prefetcher
+}
+
+// Returns the underlying source. The source is read from a background
+// goroutine, so it shouldn't be read or seeked directly while the
+// prefetcher is in use.
+func (self *Prefetcher) Source() io.Reader {
+	return self.source
+}
+
+// Implements [io.Reader]. The returned read length will always be multiple
+// of 4, aligning to Ebitengine's sample size. If the buffer is empty, this
+// method blocks until the background goroutine reads more data.
+//
+// After the prefetcher is closed, Read always returns [io.EOF].
+func (self *Prefetcher) Read(buffer []byte) (int, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	buffer = buffer[0 : len(buffer) - (len(buffer) & 0b11)]
+	if len(buffer) == 0 { return 0, nil }
+	for self.buffer.Len() < 4 && self.err == nil && !self.closed {
+		self.cond.Wait()
+	}
+	if self.closed { return 0, io.EOF }
+	if self.buffer.Len() < 4 { return 0, self.err }
+
+	// copy whole samples from the ring buffer
+	available := self.buffer.Len()
+	if len(buffer) > available {
+		buffer = buffer[0 : available - (available & 0b11)]
+	}
+	n, _ := self.buffer.Read(buffer)
+	self.cond.Broadcast() // notify available space
+	return n, nil
+}
+
+// Implements [io.Seeker]. The prefetched data is discarded and the
+// background goroutine starts reading ahead again from the new position.
+// Relative seeks take into account the data that had been prefetched
+// but not read yet.
+//
+// If the underlying source doesn't implement [io.Seeker], [ErrNotSeekable]
+// is returned.
+func (self *Prefetcher) Seek(offset int64, whence int) (int64, error) {
+	seeker, canSeek := self.source.(io.Seeker)
+	if !canSeek { return 0, ErrNotSeekable }
+	self.sourceMutex.Lock()
+	defer self.sourceMutex.Unlock()
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	if whence == io.SeekCurrent { offset -= int64(self.buffer.Len()) }
+	position, err := seeker.Seek(offset, whence)
+	self.buffer.Reset()
+	self.err = nil
+	self.cond.Broadcast()
+	return position, err
+}
+
+// Implements [io.Closer]. Stops the background goroutine. If the goroutine
+// is blocked reading from the source, it will stop after the read returns.
+// This method always returns nil.
+func (self *Prefetcher) Close() error {
+	self.mutex.Lock()
+	self.closed = true
+	self.cond.Broadcast()
+	self.mutex.Unlock()
+	return nil
+}
+
+// Background goroutine that keeps the ring buffer filled.
+func (self *Prefetcher) run() {
+	chunk := make([]byte, prefetcherMaxChunkSize)
+	for {
+		// wait until there's space to fill
+		self.mutex.Lock()
+		for !self.closed && (self.err != nil || self.buffer.Free() == 0) {
+			self.cond.Wait()
+		}
+		if self.closed {
+			self.mutex.Unlock()
+			return
+		}
+		space := self.buffer.Free()
+		self.mutex.Unlock()
+
+		// read from the source. The source mutex is kept until the data
+		// is stored so seeks can't happen in between
+		self.sourceMutex.Lock()
+		if space > len(chunk) { space = len(chunk) }
+		n, err := self.source.Read(chunk[0 : space])
+		self.mutex.Lock()
+		self.buffer.Write(chunk[0 : n])
+		self.err = err
+		self.cond.Broadcast()
+		self.mutex.Unlock()
+		self.sourceMutex.Unlock()
+	}
+}
@@ -0,0 +1,44 @@
+package edau
+
+import "io"
+import "bytes"
+import "testing"
+
+func TestPrefetcher(t *testing.T) {
+	input := testSineL16(440, 0.5, testSampleRate)
+	prefetcher := NewPrefetcher(bytes.NewReader(input), 1024)
+	defer prefetcher.Close()
+
+	output, err := io.ReadAll(prefetcher)
+	if err != nil { t.Fatal(err) }
+	if !bytes.Equal(input, output) {
+		t.Fatalf("prefetched output doesn't match the input")
+	}
+
+	// seek back to the middle and read again
+	position, err := prefetcher.Seek(int64(len(input)/2), io.SeekStart)
+	if err != nil { t.Fatal(err) }
+	if position != int64(len(input)/2) {
+		t.Fatalf("expected seek position %d, got %d", len(input)/2, position)
+	}
+	output, err = io.ReadAll(prefetcher)
+	if err != nil { t.Fatal(err) }
+	if !bytes.Equal(input[len(input)/2 : ], output) {
+		t.Fatalf("prefetched output after seek doesn't match the input")
+	}
+}
+
+func TestPrefetcherSeekCurrent(t *testing.T) {
+	input := testSineL16(440, 0.5, testSampleRate)
+	prefetcher := NewPrefetcher(bytes.NewReader(input), 4096)
+	defer prefetcher.Close()
+
+	buffer := make([]byte, 400)
+	_, err := io.ReadFull(prefetcher, buffer)
+	if err != nil { t.Fatal(err) }
+	position, err := prefetcher.Seek(0, io.SeekCurrent)
+	if err != nil { t.Fatal(err) }
+	if position != 400 {
+		t.Fatalf("expected current position 400, got %d", position)
+	}
+}
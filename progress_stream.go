@@ -0,0 +1,71 @@
+package edau
+
+import "io"
+
+// A ProgressStream wraps an audio stream and reports the reading progress
+// through a callback. This is mainly useful to make long offline processing
+// jobs observable, like resampling or normalizing whole files.
+type ProgressStream struct {
+	source StdAudioStream
+	everyBytes int64
+	callback func(done, total int64)
+	position int64
+	nextReport int64
+	lastReport int64 // position at the last callback invocation, -1 if none
+}
+
+// Creates a new [ProgressStream]. The callback is invoked from Read each
+// time another everyBytes bytes have been read, and also once when the
+// source reaches [io.EOF]. The callback receives the current position in
+// the stream and its total length, as reported by Length(). If a single
+// read crosses multiple reporting points, the callback is only invoked once.
+//
+// everyBytes must be strictly positive and the callback can't be nil.
+// This method will panic otherwise.
+func NewProgressStream(source StdAudioStream, everyBytes int64, callback func(done, total int64)) *ProgressStream {
+	if everyBytes <= 0 { panic("NewProgressStream everyBytes must be strictly positive") }
+	if callback == nil { panic("NewProgressStream callback can't be nil") }
+	return &ProgressStream {
+		source: source,
+		everyBytes: everyBytes,
+		callback: callback,
+		nextReport: everyBytes,
+		lastReport: -1,
+	}
+}
+
+// Returns the underlying source.
+func (self *ProgressStream) Source() StdAudioStream {
+	return self.source
+}
+
+// Implements [io.Reader].
+func (self *ProgressStream) Read(buffer []byte) (int, error) {
+	n, err := self.source.Read(buffer)
+	self.position += int64(n)
+	if self.position >= self.nextReport || (err == io.EOF && self.position != self.lastReport) {
+		self.updateNextReport()
+		self.lastReport = self.position
+		self.callback(self.position, self.source.Length())
+	}
+	return n, err
+}
+
+// Implements [io.Seeker]. Seeking updates the reported progress, but
+// doesn't invoke the callback by itself.
+func (self *ProgressStream) Seek(offset int64, whence int) (int64, error) {
+	position, err := self.source.Seek(offset, whence)
+	if err != nil { return position, err }
+	self.position = position
+	self.updateNextReport()
+	return position, nil
+}
+
+// Returns the length of the underlying stream.
+func (self *ProgressStream) Length() int64 {
+	return self.source.Length()
+}
+
+func (self *ProgressStream) updateNextReport() {
+	self.nextReport = (self.position/self.everyBytes + 1)*self.everyBytes
+}
@@ -0,0 +1,43 @@
+package edau
+
+import "io"
+
+// Creates a stream that plays the given source the given number of times
+// back to back, and then returns [io.EOF]. Unlike [Looper], there are no
+// loop points to configure: after each [io.EOF], the source is simply
+// rewound to the start.
+//
+// The first playback starts from the source's current position. times
+// must be at least 1. This method will panic otherwise.
+func NewRepeat(source StdAudioStream, times int) io.Reader {
+	if times < 1 { panic("NewRepeat times must be at least 1") }
+	return &repeatStream{ source: source, remaining: times }
+}
+
+type repeatStream struct {
+	source StdAudioStream
+	remaining int // playbacks left, including the current one
+	roundBytes int64 // bytes read in the current playback
+}
+
+func (self *repeatStream) Read(buffer []byte) (int, error) {
+	bytesRead := 0
+	for bytesRead < len(buffer) {
+		if self.remaining <= 0 { return bytesRead, io.EOF }
+
+		n, err := self.source.Read(buffer[bytesRead : ])
+		bytesRead += n
+		self.roundBytes += int64(n)
+		if err != io.EOF { return bytesRead, err }
+
+		// rewind the source for the next playback
+		self.remaining -= 1
+		if self.roundBytes == 0 { self.remaining = 0 } // empty source, stop
+		self.roundBytes = 0
+		if self.remaining > 0 {
+			_, err = self.source.Seek(0, io.SeekStart)
+			if err != nil { return bytesRead, err }
+		}
+	}
+	return bytesRead, nil
+}
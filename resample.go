@@ -0,0 +1,57 @@
+package edau
+
+import "math"
+
+// Resamples the L16, 2 channel, little-endian audio in src and writes the
+// result to dst in the same format. This is the offline counterpart of the
+// [SpeedShifter]: the ratio is the number of source samples advanced per
+// output sample, so a ratio of 2 halves the length of the audio and a
+// ratio of 0.5 doubles it. To convert from one sample rate to another, use
+// ratio = fromRate/toRate.
+//
+// The interpolator receives windows of the given size, which must be
+// multiple of 2, and the samples beyond the edges of src are considered to
+// be equal to the first and last samples. Only a couple of windows are
+// allocated per call, there are no per-sample allocations.
+//
+// Returns the number of bytes written to dst, which will be the bytes
+// required for ceil(srcSamples/ratio) samples, or less if dst is too small.
+func ResampleL16(dst, src []byte, ratio float64, interp InterpolatorFunc, window int) int {
+	if ratio <= 0 { panic("ResampleL16 ratio must be strictly positive") }
+	if window < 2 || window % 2 != 0 { panic("ResampleL16 window must be an even number >= 2") }
+	srcFrames := len(src)/4
+	if srcFrames == 0 { return 0 }
+	dstFrames := int(math.Ceil(float64(srcFrames)/ratio))
+	if dstFrames > len(dst)/4 { dstFrames = len(dst)/4 }
+
+	windows := make([]float64, window*2)
+	left, right := windows[ : window], windows[window : ]
+	windowIndex := math.MinInt // index of the source frame at the window's center
+	centerOffset := window/2 - 1
+	for k := 0; k < dstFrames; k++ {
+		position := float64(k)*ratio
+		index := int(position)
+
+		// update the windows, shifting them if possible
+		if index != windowIndex {
+			start := 0
+			if shift := index - windowIndex; shift > 0 && shift < window {
+				copy(left, left[shift : ])
+				copy(right, right[shift : ])
+				start = window - shift
+			}
+			for j := start; j < window; j++ {
+				srcIndex := index - centerOffset + j
+				if srcIndex < 0 { srcIndex = 0 }
+				if srcIndex >= srcFrames { srcIndex = srcFrames - 1 }
+				l, r := GetSampleAsI16(src[srcIndex*4 : ])
+				left[j], right[j] = float64(l), float64(r)
+			}
+			windowIndex = index
+		}
+
+		x := float64(centerOffset) + position - float64(index)
+		StoreF64SampleAsL16(dst[k*4 : ], interp(left, x), interp(right, x))
+	}
+	return dstFrames*4
+}
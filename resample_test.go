@@ -0,0 +1,56 @@
+package edau
+
+import "math"
+import "testing"
+
+func TestResampleL16(t *testing.T) {
+	src := testSineL16(440, 0.5, testSampleRate)
+	for _, ratio := range []float64{ 0.5, 0.9, 1.0, 1.5, 2.0 } {
+		dst := make([]byte, int(math.Ceil(float64(len(src)/4)/ratio))*4)
+		n := ResampleL16(dst, src, ratio, InterpHermite6Pt3Ord, 6)
+		if n != len(dst) { t.Fatalf("ratio %.1f: expected %d bytes, got %d", ratio, len(dst), n) }
+
+		// the result must be a sine at 440*ratio Hz
+		expected := testSineL16(440*ratio, 0.5, testSampleRate)
+		for i := 0; i + 4 <= n && i + 4 <= len(expected); i += 4 {
+			got, _ := GetSampleAsF64(dst[i : ])
+			want, _ := GetSampleAsF64(expected[i : ])
+			if math.Abs(got - want) > 0.01 {
+				t.Fatalf("ratio %.1f: sample %d expected %f, got %f", ratio, i/4, want, got)
+			}
+		}
+	}
+}
+
+func BenchmarkResampleL16(b *testing.B) {
+	src := testSineL16(440, 0.5, testSampleRate)
+	dst := make([]byte, len(src)*2)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ResampleL16(dst, src, 0.9, InterpHermite6Pt3Ord, 6)
+	}
+}
+
+// Naive convert-interpolate-convert approach for comparison.
+func BenchmarkResampleNaive(b *testing.B) {
+	src := testSineL16(440, 0.5, testSampleRate)
+	dst := make([]byte, len(src)*2)
+	srcFrames := len(src)/4
+	dstFrames := int(math.Ceil(float64(srcFrames)/0.9))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for k := 0; k < dstFrames; k++ {
+			position := float64(k)*0.9
+			index := int(position)
+			left, right := make([]float64, 6), make([]float64, 6)
+			for j := 0; j < 6; j++ {
+				srcIndex := index - 2 + j
+				if srcIndex < 0 { srcIndex = 0 }
+				if srcIndex >= srcFrames { srcIndex = srcFrames - 1 }
+				left[j], right[j] = GetSampleAsF64(src[srcIndex*4 : ])
+			}
+			x := 2 + position - float64(index)
+			StoreNormF64SampleAsL16(dst[k*4 : ], InterpHermite6Pt3Ord(left, x), InterpHermite6Pt3Ord(right, x))
+		}
+	}
+}
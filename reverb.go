@@ -0,0 +1,226 @@
+package edau
+
+import "io"
+import "sync"
+
+// Comb and allpass delay lengths, in samples at 44.1kHz. These are the
+// classic Freeverb tunings, which are adjusted for other sample rates.
+var reverbCombTunings    = [...]int{ 1116, 1188, 1277, 1356, 1422, 1491, 1557, 1617 }
+var reverbAllpassTunings = [...]int{ 556, 441, 341, 225 }
+
+const reverbInputGain = 0.015 // keeps the sum of comb filters in range
+const reverbWetScale  = 3.0
+const reverbAllpassFeedback = 0.5
+
+// A Reverb wraps an audio stream and adds reverberation to it. The
+// implementation follows the classic Schroeder design (in its Freeverb
+// flavor): eight parallel lowpass-feedback comb filters followed by four
+// allpass filters in series.
+//
+// The delay lines are kept in float64 to avoid quantization noise on
+// quiet tails, using roughly 200KiB at 44.1kHz.
+type Reverb struct {
+	mutex sync.Mutex
+	source io.Reader
+	roomSize float64
+	damping float64
+	mix float64
+
+	combs [len(reverbCombTunings)]reverbComb
+	allpasses [len(reverbAllpassTunings)]reverbLine
+}
+
+// Creates a new [Reverb]. roomSize controls the length of the reverb tail,
+// damping how fast the high frequencies fade out, and mix the balance between
+// the original and the reverberated signals (0 is fully dry, 1 fully wet).
+// All values must be in [0, 1]. This method will panic otherwise.
+// If the sample rate is 0, [DefaultSampleRate] is used.
+func NewReverb(source io.Reader, roomSize, damping, mix float64, sampleRate int) *Reverb {
+	sampleRate = resolveSampleRate(sampleRate, "NewReverb")
+	assertUnitRangeParam("roomSize", roomSize)
+	assertUnitRangeParam("damping", damping)
+	assertMixValidity(mix)
+
+	reverb := &Reverb {
+		source: source,
+		roomSize: roomSize,
+		damping: damping,
+		mix: mix,
+	}
+	for i, tuning := range reverbCombTunings {
+		reverb.combs[i].line = newReverbLine(reverbScaleTuning(tuning, sampleRate))
+	}
+	for i, tuning := range reverbAllpassTunings {
+		reverb.allpasses[i] = newReverbLine(reverbScaleTuning(tuning, sampleRate))
+	}
+	return reverb
+}
+
+// Returns the underlying source.
+func (self *Reverb) Source() io.Reader {
+	return self.source
+}
+
+// Returns the currently configured room size.
+func (self *Reverb) RoomSize() float64 {
+	self.mutex.Lock()
+	roomSize := self.roomSize
+	self.mutex.Unlock()
+	return roomSize
+}
+
+// Sets the room size, which must be in [0, 1]. This method will
+// panic if the value is invalid.
+func (self *Reverb) SetRoomSize(roomSize float64) {
+	assertUnitRangeParam("roomSize", roomSize)
+	self.mutex.Lock()
+	self.roomSize = roomSize
+	self.mutex.Unlock()
+}
+
+// Returns the currently configured damping.
+func (self *Reverb) Damping() float64 {
+	self.mutex.Lock()
+	damping := self.damping
+	self.mutex.Unlock()
+	return damping
+}
+
+// Sets the damping, which must be in [0, 1]. This method will
+// panic if the value is invalid.
+func (self *Reverb) SetDamping(damping float64) {
+	assertUnitRangeParam("damping", damping)
+	self.mutex.Lock()
+	self.damping = damping
+	self.mutex.Unlock()
+}
+
+// Returns the currently configured mix factor.
+func (self *Reverb) Mix() float64 {
+	self.mutex.Lock()
+	mix := self.mix
+	self.mutex.Unlock()
+	return mix
+}
+
+// Sets the mix factor, which must be in [0, 1]. This method will
+// panic if the value is invalid.
+func (self *Reverb) SetMix(mix float64) {
+	assertMixValidity(mix)
+	self.mutex.Lock()
+	self.mix = mix
+	self.mutex.Unlock()
+}
+
+// Implements [io.Reader]. The returned read length will always be
+// multiple of 4, aligning to Ebitengine's sample size.
+func (self *Reverb) Read(buffer []byte) (int, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	feedback := self.roomSize*0.28 + 0.7
+	damp := self.damping*0.4
+	n, err := readFrames(self.source, buffer)
+	for i := 0; i < n; i += 4 {
+		left, right := GetSampleAsF64(buffer[i : ])
+		inLeft, inRight := left*reverbInputGain, right*reverbInputGain
+
+		// parallel comb filters
+		var wetLeft, wetRight float64
+		for c := 0; c < len(self.combs); c++ {
+			outLeft, outRight := self.combs[c].Next(inLeft, inRight, feedback, damp)
+			wetLeft  += outLeft
+			wetRight += outRight
+		}
+
+		// series allpass filters
+		for a := 0; a < len(self.allpasses); a++ {
+			bufLeft, bufRight := self.allpasses[a].Peek()
+			self.allpasses[a].Push(
+				wetLeft  + bufLeft*reverbAllpassFeedback,
+				wetRight + bufRight*reverbAllpassFeedback,
+			)
+			wetLeft, wetRight = bufLeft - wetLeft, bufRight - wetRight
+		}
+
+		// mix and store
+		dry, wet := 1.0 - self.mix, self.mix*reverbWetScale
+		StoreNormF64SampleAsL16(buffer[i : ], left*dry + wetLeft*wet, right*dry + wetRight*wet)
+	}
+	return n, err
+}
+
+// Implements [io.Seeker]. The reverb tail is cleared after seeking.
+//
+// If the underlying source doesn't implement [io.Seeker], [ErrNotSeekable]
+// is returned.
+func (self *Reverb) Seek(offset int64, whence int) (int64, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	position, err := seekSource(self.source, offset, whence)
+	for i := 0; i < len(self.combs); i++ {
+		self.combs[i].line.Reset()
+		self.combs[i].storeLeft, self.combs[i].storeRight = 0, 0
+	}
+	for i := 0; i < len(self.allpasses); i++ {
+		self.allpasses[i].Reset()
+	}
+	return position, err
+}
+
+// --- helper types and functions ---
+
+// Comb filter with a one-pole lowpass in the feedback path.
+type reverbComb struct {
+	line reverbLine
+	storeLeft float64
+	storeRight float64
+}
+
+func (self *reverbComb) Next(left, right float64, feedback, damp float64) (float64, float64) {
+	outLeft, outRight := self.line.Peek()
+	self.storeLeft  = outLeft*(1.0 - damp)  + self.storeLeft*damp
+	self.storeRight = outRight*(1.0 - damp) + self.storeRight*damp
+	self.line.Push(left + self.storeLeft*feedback, right + self.storeRight*feedback)
+	return outLeft, outRight
+}
+
+// Like delayLine, but storing float64 values, so low level signals can
+// recirculate through the filters without being truncated to 16 bits.
+type reverbLine struct {
+	buffer []float64 // interleaved left and right values
+	index int // position of the oldest sample
+}
+
+func newReverbLine(frames int) reverbLine {
+	return reverbLine{ buffer: make([]float64, frames*2) }
+}
+
+// Returns the oldest sample in the line.
+func (self *reverbLine) Peek() (float64, float64) {
+	return self.buffer[self.index], self.buffer[self.index + 1]
+}
+
+// Overwrites the oldest sample in the line with the given one, which
+// becomes the newest sample.
+func (self *reverbLine) Push(left, right float64) {
+	self.buffer[self.index], self.buffer[self.index + 1] = left, right
+	self.index += 2
+	if self.index >= len(self.buffer) { self.index = 0 }
+}
+
+// Fills the line with silence.
+func (self *reverbLine) Reset() {
+	for i := range self.buffer { self.buffer[i] = 0 }
+	self.index = 0
+}
+
+func reverbScaleTuning(tuning int, sampleRate int) int {
+	frames := (tuning*sampleRate)/44100
+	if frames < 1 { return 1 }
+	return frames
+}
+
+func assertUnitRangeParam(name string, value float64) {
+	if value < 0 || value > 1.0 { panic(name + " must be in [0, 1]") }
+}
@@ -0,0 +1,73 @@
+package edau
+
+import "io"
+import "bytes"
+import "testing"
+
+func TestReverbImpulseTail(t *testing.T) {
+	// one second of silence with an impulse at the start
+	input := make([]byte, testSampleRate*4)
+	StoreNormF64SampleAsL16(input, 1.0, 1.0)
+
+	reverb := NewReverb(bytes.NewReader(input), 0.8, 0.3, 1.0, testSampleRate)
+	output, err := io.ReadAll(reverb)
+	if err != nil { t.Fatal(err) }
+	if len(output) != len(input) {
+		t.Fatalf("expected %d bytes of output, got %d", len(input), len(output))
+	}
+
+	// measure energy in consecutive 100ms windows
+	const windowSize = (testSampleRate/10)*4
+	var energies []float64
+	for start := 0; start + windowSize <= len(output); start += windowSize {
+		energy := 0.0
+		for i := start; i < start + windowSize; i += 4 {
+			left, right := GetSampleAsF64(output[i : ])
+			energy += left*left + right*right
+		}
+		energies = append(energies, energy)
+	}
+
+	// the tail must exist and decay over time
+	if energies[1] == 0 {
+		t.Fatalf("expected a reverb tail after the impulse, got silence")
+	}
+	for i := 2; i < len(energies); i++ {
+		if energies[i] > energies[i - 1] {
+			t.Fatalf("expected decaying tail, but window %d has more energy than window %d (%f vs %f)", i, i - 1, energies[i], energies[i - 1])
+		}
+	}
+	if energies[len(energies) - 1] >= energies[1]/10 {
+		t.Fatalf("expected the tail to decay significantly, got %f at the end vs %f at the start", energies[len(energies) - 1], energies[1])
+	}
+}
+
+func TestReverbQuietTail(t *testing.T) {
+	// the tail of a quiet impulse must be a scaled down version of the
+	// tail of a loud one, not a grainy tail that dies out early
+	tailEnergies := func(amplitude float64) []float64 {
+		input := make([]byte, testSampleRate*4)
+		StoreNormF64SampleAsL16(input, amplitude, amplitude)
+		output, err := io.ReadAll(NewReverb(bytes.NewReader(input), 0.8, 0.3, 1.0, testSampleRate))
+		if err != nil { t.Fatal(err) }
+		const windowSize = (testSampleRate/10)*4
+		var energies []float64
+		for start := windowSize; start + windowSize <= len(output); start += windowSize {
+			energy := 0.0
+			for i := start; i < start + windowSize; i += 4 {
+				left, right := GetSampleAsF64(output[i : ])
+				energy += left*left + right*right
+			}
+			energies = append(energies, energy)
+		}
+		return energies
+	}
+
+	loud, quiet := tailEnergies(1.0), tailEnergies(0.03) // ~ -30dBFS
+	for i := 0; i < 3; i++ { // later windows get closer to the output's 16-bit floor
+		ratio := quiet[i]/(loud[i]*0.03*0.03)
+		if ratio < 0.8 || ratio > 1.25 {
+			t.Fatalf("window %d: expected the quiet tail to match the scaled loud tail (ratio %f)", i + 1, ratio)
+		}
+	}
+}
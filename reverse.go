@@ -0,0 +1,65 @@
+package edau
+
+import "io"
+
+const reverseBlockSize = 16384 // must be multiple of 4
+
+// Creates a stream that plays the given source backwards, from its end
+// to its start. The source is read backwards in chunks: each chunk is
+// read with a seek followed by regular reads, and then the order of its
+// samples is reversed. This means that the source must be seekable, and
+// reversing compressed streams can be quite expensive.
+//
+// The source's current position is ignored, and it's left in an undefined
+// position after reading.
+func NewReverse(source StdAudioStream) io.Reader {
+	length := source.Length()
+	return &reverseStream {
+		source: source,
+		position: length - (length & 0b11),
+	}
+}
+
+type reverseStream struct {
+	source StdAudioStream
+	position int64 // start of the last block read from the source
+	block []byte
+	pending []byte // reversed data not served yet
+}
+
+func (self *reverseStream) Read(buffer []byte) (int, error) {
+	if len(self.pending) == 0 {
+		if self.position <= 0 { return 0, io.EOF }
+		err := self.readPrevBlock()
+		if err != nil { return 0, err }
+	}
+
+	n := copy(buffer, self.pending)
+	self.pending = self.pending[n : ]
+	return n, nil
+}
+
+// Reads the block before the current position and reverses it.
+func (self *reverseStream) readPrevBlock() error {
+	if self.block == nil { self.block = make([]byte, reverseBlockSize) }
+	blockStart := self.position - reverseBlockSize
+	if blockStart < 0 { blockStart = 0 }
+	block := self.block[0 : self.position - blockStart]
+
+	_, err := self.source.Seek(blockStart, io.SeekStart)
+	if err != nil { return err }
+	_, err = io.ReadFull(self.source, block)
+	if err != nil { return err }
+
+	// reverse the order of the samples
+	for i, j := 0, len(block) - 4; i < j; i, j = i + 4, j - 4 {
+		leftI, rightI := GetSampleAsI16(block[i : ])
+		leftJ, rightJ := GetSampleAsI16(block[j : ])
+		StoreL16Sample(block[i : ], leftJ, rightJ)
+		StoreL16Sample(block[j : ], leftI, rightI)
+	}
+
+	self.position = blockStart
+	self.pending = block
+	return nil
+}
@@ -0,0 +1,75 @@
+package edau
+
+import "io"
+
+// A RingBuffer is a fixed capacity FIFO byte buffer. Writes append data at
+// the end and reads consume data from the start, with the storage wrapping
+// around so no allocations or copies beyond the data itself are needed.
+//
+// RingBuffer implements [io.Reader] and [io.Writer], but it's not safe for
+// concurrent use. Create it with [NewRingBuffer].
+type RingBuffer struct {
+	buffer []byte
+	start int
+	size int
+}
+
+// Creates a new [RingBuffer] with the given capacity, in bytes.
+func NewRingBuffer(capacity int) *RingBuffer {
+	if capacity < 1 { panic("NewRingBuffer capacity must be at least 1") }
+	return &RingBuffer{ buffer: make([]byte, capacity) }
+}
+
+// Implements [io.Writer]. If there's not enough free space for the whole
+// data, as much as possible is written and [io.ErrShortWrite] is returned.
+func (self *RingBuffer) Write(data []byte) (int, error) {
+	n := len(self.buffer) - self.size
+	if n > len(data) { n = len(data) }
+
+	end := self.start + self.size
+	if end >= len(self.buffer) { end -= len(self.buffer) }
+	firstPart := copy(self.buffer[end : ], data[0 : n])
+	copy(self.buffer, data[firstPart : n])
+	self.size += n
+
+	if n < len(data) { return n, io.ErrShortWrite }
+	return n, nil
+}
+
+// Implements [io.Reader]. If the ring buffer is empty, [io.EOF] is returned.
+func (self *RingBuffer) Read(buffer []byte) (int, error) {
+	if self.size == 0 {
+		if len(buffer) == 0 { return 0, nil }
+		return 0, io.EOF
+	}
+
+	n := self.size
+	if n > len(buffer) { n = len(buffer) }
+	firstPart := copy(buffer[0 : n], self.buffer[self.start : ])
+	copy(buffer[firstPart : n], self.buffer)
+	self.start += n
+	if self.start >= len(self.buffer) { self.start -= len(self.buffer) }
+	self.size -= n
+	return n, nil
+}
+
+// Returns the number of bytes available for reading.
+func (self *RingBuffer) Len() int {
+	return self.size
+}
+
+// Returns the capacity of the ring buffer, in bytes.
+func (self *RingBuffer) Cap() int {
+	return len(self.buffer)
+}
+
+// Returns the number of bytes that can be written before the
+// buffer becomes full. Equivalent to Cap() - Len().
+func (self *RingBuffer) Free() int {
+	return len(self.buffer) - self.size
+}
+
+// Discards all the data in the buffer.
+func (self *RingBuffer) Reset() {
+	self.start, self.size = 0, 0
+}
@@ -0,0 +1,47 @@
+package edau
+
+import "io"
+import "bytes"
+import "testing"
+
+func TestRingBuffer(t *testing.T) {
+	ring := NewRingBuffer(10)
+	if ring.Cap() != 10 || ring.Len() != 0 || ring.Free() != 10 {
+		t.Fatalf("unexpected initial state (cap %d, len %d, free %d)", ring.Cap(), ring.Len(), ring.Free())
+	}
+
+	// empty read
+	buffer := make([]byte, 16)
+	n, err := ring.Read(buffer)
+	if n != 0 || err != io.EOF {
+		t.Fatalf("expected (0, EOF) on empty read, got (%d, %v)", n, err)
+	}
+
+	// write, partially read, and write again to force wrapping
+	n, err = ring.Write([]byte{ 1, 2, 3, 4, 5, 6, 7 })
+	if n != 7 || err != nil { t.Fatalf("unexpected write result (%d, %v)", n, err) }
+	n, err = ring.Read(buffer[0 : 5])
+	if n != 5 || err != nil { t.Fatalf("unexpected read result (%d, %v)", n, err) }
+	if !bytes.Equal(buffer[0 : 5], []byte{ 1, 2, 3, 4, 5 }) {
+		t.Fatalf("unexpected read data %v", buffer[0 : 5])
+	}
+	n, err = ring.Write([]byte{ 8, 9, 10, 11, 12, 13, 14, 15, 16 })
+	if n != 8 || err != io.ErrShortWrite {
+		t.Fatalf("expected (8, ErrShortWrite) on overflowing write, got (%d, %v)", n, err)
+	}
+	if ring.Len() != 10 || ring.Free() != 0 {
+		t.Fatalf("expected full buffer, got len %d", ring.Len())
+	}
+
+	n, err = ring.Read(buffer)
+	if n != 10 || err != nil { t.Fatalf("unexpected read result (%d, %v)", n, err) }
+	expected := []byte{ 6, 7, 8, 9, 10, 11, 12, 13, 14, 15 }
+	if !bytes.Equal(buffer[0 : 10], expected) {
+		t.Fatalf("expected %v, got %v", expected, buffer[0 : 10])
+	}
+
+	// reset
+	ring.Write([]byte{ 1, 2, 3 })
+	ring.Reset()
+	if ring.Len() != 0 { t.Fatalf("expected empty buffer after reset") }
+}
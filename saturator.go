@@ -0,0 +1,90 @@
+package edau
+
+import "io"
+import "math"
+import "sync"
+
+// Samples below this level pass through the [Saturator] untouched.
+const saturatorKnee = 0.7
+
+// A Saturator wraps an audio stream and applies gentle soft clipping, so
+// hot peaks are rounded off smoothly instead of being squared off by the
+// hard clipping of the final L16 conversion. Unlike [Distortion], the goal
+// is transparent loudness, not an obvious effect: samples below a fixed
+// knee (0.7) are left untouched, and only the range above it is compressed
+// with a tanh curve that approaches full scale asymptotically.
+//
+// The drive is applied as an input gain before the curve, so values above
+// 1 make the stream louder and push more of it into the saturation range.
+type Saturator struct {
+	mutex sync.Mutex
+	source io.Reader
+	drive float64
+}
+
+// Creates a new [Saturator]. The drive must be strictly positive, with 1
+// leaving the input level unchanged. This method will panic if the drive
+// is invalid.
+func NewSaturator(source io.Reader, drive float64) *Saturator {
+	assertDriveValidity(drive)
+	return &Saturator{ source: source, drive: drive }
+}
+
+// Returns the underlying source.
+func (self *Saturator) Source() io.Reader {
+	return self.source
+}
+
+// Returns the currently configured drive.
+func (self *Saturator) Drive() float64 {
+	self.mutex.Lock()
+	drive := self.drive
+	self.mutex.Unlock()
+	return drive
+}
+
+// Sets the drive, which must be strictly positive. This method will
+// panic if the value is invalid.
+func (self *Saturator) SetDrive(drive float64) {
+	assertDriveValidity(drive)
+	self.mutex.Lock()
+	self.drive = drive
+	self.mutex.Unlock()
+}
+
+// Implements [io.Reader]. The returned read length will always be
+// multiple of 4, aligning to Ebitengine's sample size.
+func (self *Saturator) Read(buffer []byte) (int, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	n, err := readFrames(self.source, buffer)
+	for i := 0; i < n; i += 4 {
+		left, right := GetSampleAsF64(buffer[i : ])
+		left  = saturate(left*self.drive)
+		right = saturate(right*self.drive)
+		StoreNormF64SampleAsL16(buffer[i : ], left, right)
+	}
+	return n, err
+}
+
+// Implements [io.Seeker]. Saturation is stateless, so this simply
+// seeks the underlying source.
+//
+// If the underlying source doesn't implement [io.Seeker], [ErrNotSeekable]
+// is returned.
+func (self *Saturator) Seek(offset int64, whence int) (int64, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	return seekSource(self.source, offset, whence)
+}
+
+// Linear below the knee, tanh above it. The curve has slope 1 at
+// the knee, so the transition is smooth, and it never exceeds 1.
+func saturate(value float64) float64 {
+	magnitude := math.Abs(value)
+	if magnitude <= saturatorKnee { return value }
+	const headroom = 1.0 - saturatorKnee
+	magnitude = saturatorKnee + headroom*math.Tanh((magnitude - saturatorKnee)/headroom)
+	return math.Copysign(magnitude, value)
+}
@@ -0,0 +1,94 @@
+package edau
+
+import "io"
+import "errors"
+
+// A Section wraps an audio stream and exposes only a portion of it, as
+// if it was a separate stream. Positions are relative to the section, so
+// seeking to 0 goes to the start of the section in the underlying stream,
+// and [Section.Length] returns the length of the section.
+//
+// The underlying stream shouldn't be used directly while the section is
+// in use, as the section relies on the stream's position.
+type Section struct {
+	source StdAudioStream
+	start int64
+	end int64
+	position int64 // relative to start
+	needsSeek bool
+}
+
+// Creates a new [Section] for the [startByte, endByte) range of the given
+// stream. Both values must be multiples of 4, startByte can't be negative
+// and endByte can't be smaller than startByte. This method will panic if
+// any of those are not respected. endByte may go beyond the length of the
+// stream, but the section will end earlier in that case.
+func NewSection(source StdAudioStream, startByte, endByte int64) *Section {
+	if startByte & 0b11 != 0 { panic("startByte must be multiple of 4") }
+	if endByte   & 0b11 != 0 { panic("endByte must be multiple of 4") }
+	if startByte < 0 { panic("startByte must be >= 0") }
+	if endByte < startByte { panic("endByte can't be smaller than startByte") }
+	return &Section {
+		source: source,
+		start: startByte,
+		end: endByte,
+		needsSeek: true,
+	}
+}
+
+// Returns the underlying source.
+func (self *Section) Source() StdAudioStream {
+	return self.source
+}
+
+// Implements [io.Reader].
+func (self *Section) Read(buffer []byte) (int, error) {
+	if self.needsSeek {
+		_, err := self.source.Seek(self.start + self.position, io.SeekStart)
+		if err != nil { return 0, err }
+		self.needsSeek = false
+	}
+
+	remaining := (self.end - self.start) - self.position
+	if remaining <= 0 { return 0, io.EOF }
+	if int64(len(buffer)) > remaining {
+		buffer = buffer[0 : remaining]
+	}
+	n, err := self.source.Read(buffer)
+	self.position += int64(n)
+	if err == nil && self.position >= self.end - self.start {
+		err = io.EOF
+	}
+	return n, err
+}
+
+// Implements [io.Seeker]. Offsets are relative to the section.
+func (self *Section) Seek(offset int64, whence int) (int64, error) {
+	var position int64
+	switch whence {
+	case io.SeekStart:
+		position = offset
+	case io.SeekCurrent:
+		position = self.position + offset
+	case io.SeekEnd:
+		position = self.Length() + offset
+	default:
+		return self.position, errors.New("Section.Seek: invalid whence")
+	}
+	if position < 0 { return self.position, errors.New("Section.Seek: negative position") }
+
+	_, err := self.source.Seek(self.start + position, io.SeekStart)
+	if err != nil { return self.position, err }
+	self.position = position
+	self.needsSeek = false
+	return position, nil
+}
+
+// Returns the length of the section, in bytes. This is endByte - startByte,
+// unless the underlying stream ends earlier.
+func (self *Section) Length() int64 {
+	end := self.source.Length()
+	if end > self.end { end = self.end }
+	if end < self.start { return 0 }
+	return end - self.start
+}
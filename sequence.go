@@ -0,0 +1,85 @@
+package edau
+
+import "io"
+import "sync"
+
+// A Sequence plays multiple audio streams back to back, like a playlist.
+// Each source is read until [io.EOF], and then the sequence moves seamlessly
+// to the next one, within the same Read call if necessary.
+//
+// More sources can be queued during playback with [Sequence.Append].
+type Sequence struct {
+	mutex sync.Mutex
+	sources []io.Reader
+	index int
+	onAdvance func(int)
+}
+
+// Creates a new [Sequence] with the given sources.
+func NewSequence(sources ...io.Reader) *Sequence {
+	return &Sequence {
+		sources: append([]io.Reader(nil), sources...),
+	}
+}
+
+// Adds a new source at the end of the sequence. If the sequence had
+// already been exhausted, the next Read will resume from this source.
+func (self *Sequence) Append(source io.Reader) {
+	self.mutex.Lock()
+	self.sources = append(self.sources, source)
+	self.mutex.Unlock()
+}
+
+// Sets a function to be called each time the sequence moves to the next
+// source. The function receives the index of the new source. When the last
+// source is exhausted, the function is called with an index equal to the
+// number of sources.
+//
+// The function is called from Read, typically on Ebitengine's audio
+// goroutine, so it must return quickly. The function can be set to nil
+// to disable the notifications.
+func (self *Sequence) OnAdvance(fn func(index int)) {
+	self.mutex.Lock()
+	self.onAdvance = fn
+	self.mutex.Unlock()
+}
+
+// Returns the index of the source being currently played.
+func (self *Sequence) Index() int {
+	self.mutex.Lock()
+	index := self.index
+	self.mutex.Unlock()
+	return index
+}
+
+// Implements [io.Reader].
+func (self *Sequence) Read(buffer []byte) (int, error) {
+	self.mutex.Lock()
+	onAdvance := self.onAdvance
+	startIndex := self.index
+	n, err := self.read(buffer)
+	endIndex := self.index
+	self.mutex.Unlock()
+
+	// notify advances outside the mutex, so the
+	// callback can still use the Sequence's methods
+	if onAdvance != nil {
+		for index := startIndex + 1; index <= endIndex; index++ {
+			onAdvance(index)
+		}
+	}
+	return n, err
+}
+
+func (self *Sequence) read(buffer []byte) (int, error) {
+	bytesRead := 0
+	for bytesRead < len(buffer) {
+		if self.index >= len(self.sources) { return bytesRead, io.EOF }
+
+		n, err := self.sources[self.index].Read(buffer[bytesRead : ])
+		bytesRead += n
+		if err != io.EOF { return bytesRead, err }
+		self.index += 1
+	}
+	return bytesRead, nil
+}
@@ -0,0 +1,51 @@
+package edau
+
+import "io"
+
+// Creates a stream that plays the given source and, once it reaches
+// [io.EOF], keeps returning silence indefinitely instead. This keeps an
+// Ebitengine player alive after the source ends, which avoids the pops of
+// stopping and restarting it. Combined with a [SwitchableSource], it allows
+// feeding new sources to the same player at any time.
+//
+// The returned stream also implements [io.Seeker]. Seeking forwards the
+// seek to the source and resumes its playback, or returns [ErrNotSeekable]
+// if the source doesn't implement [io.Seeker].
+func NewSilencePadded(source io.Reader) io.Reader {
+	return &silencePaddedStream{ source: source }
+}
+
+type silencePaddedStream struct {
+	source io.Reader
+	sourceEOF bool
+	position int64 // bytes served, to keep the silence aligned to whole samples
+}
+
+func (self *silencePaddedStream) Read(buffer []byte) (int, error) {
+	n := 0
+	if !self.sourceEOF {
+		var err error
+		n, err = self.source.Read(buffer)
+		if err != io.EOF {
+			self.position += int64(n)
+			return n, err
+		}
+		self.sourceEOF = true
+	}
+
+	// once the source is exhausted, fill the rest of the buffer with
+	// silence, completing any partial sample left by the source
+	end := len(buffer) - int((self.position + int64(len(buffer))) & 0b11)
+	if end < n { end = n }
+	for i := n; i < end; i++ { buffer[i] = 0 }
+	self.position += int64(end)
+	return end, nil
+}
+
+func (self *silencePaddedStream) Seek(offset int64, whence int) (int64, error) {
+	position, err := seekSource(self.source, offset, whence)
+	if err != nil { return position, err }
+	self.sourceEOF = false
+	self.position = position
+	return position, nil
+}
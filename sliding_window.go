@@ -0,0 +1,78 @@
+package edau
+
+// A SlidingWindow keeps the most recent values pushed into it, up to the
+// window size, and allows accessing them as a contiguous slice. This is
+// mainly useful to feed [InterpolatorFunc] functions or to implement
+// moving filters.
+//
+// Internally, the window uses a buffer several times larger than the window
+// size. Values are appended until the end of the buffer is reached, and then
+// the current window is copied back to the start of the buffer. This makes
+// pushes amortized O(1) while keeping Get allocation and copy free.
+//
+// Sliding windows must be created through [NewSlidingWindow].
+type SlidingWindow struct {
+	winSize int
+	buffer []float64
+	startIndex int
+	endIndex int
+}
+
+// Creates a new [SlidingWindow] with the given size. The window starts
+// empty. The internal buffer will hold 8 times the window size.
+func NewSlidingWindow(size int) *SlidingWindow {
+	if size < 1 { panic("NewSlidingWindow size must be at least 1") }
+	return &SlidingWindow{ winSize: size, buffer: make([]float64, size*8) }
+}
+
+// Returns the window size given on creation.
+func (self *SlidingWindow) Size() int {
+	return self.winSize
+}
+
+// Empties the window.
+func (self *SlidingWindow) Reset() {
+	self.startIndex = 0
+	self.endIndex   = 0
+}
+
+// Returns the values in the window, from oldest to most recent. Until
+// the window is filled for the first time, the slice will have fewer
+// than Size() values.
+//
+// The returned slice is only valid until the next Push or Reset.
+func (self *SlidingWindow) Get() []float64 {
+	return self.buffer[self.startIndex : self.endIndex]
+}
+
+// Adds a value to the window. If the window is full, the oldest
+// value is discarded.
+func (self *SlidingWindow) Push(value float64) {
+	if self.endIndex < len(self.buffer) {
+		self.buffer[self.endIndex] = value
+		if self.winSize == self.endIndex - self.startIndex { // window is full
+			self.startIndex += 1
+		}
+		self.endIndex += 1
+	} else {
+		if self.winSize == self.endIndex - self.startIndex { // window is full
+			copy(self.buffer, self.buffer[self.startIndex + 1 : self.endIndex])
+			self.buffer[self.winSize - 1] = value
+			self.startIndex = 0
+			self.endIndex   = self.winSize
+		} else {
+			copy(self.buffer, self.buffer[self.startIndex : self.endIndex])
+			newIndex := self.endIndex - self.startIndex
+			self.buffer[newIndex] = value
+			self.startIndex = 0
+			self.endIndex   = newIndex + 1
+		}
+	}
+}
+
+// Removes the n most recently pushed values from the window.
+// If n exceeds the number of values, the window is emptied.
+func (self *SlidingWindow) discardNewest(n int) {
+	self.endIndex -= n
+	if self.endIndex < self.startIndex { self.endIndex = self.startIndex }
+}
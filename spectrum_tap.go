@@ -0,0 +1,140 @@
+package edau
+
+import "io"
+import "math"
+import "sync"
+import "math/bits"
+
+// A SpectrumTap wraps an audio stream and passes it through unchanged,
+// while keeping a rolling FFT of the most recent audio. This can be used
+// to display a live frequency spectrum on the UI.
+//
+// The FFT is computed on each Read, over the last fftSize frames served
+// (with the left and right channels averaged and a Hann window applied),
+// so like with [Meter], the spectrum corresponds to the most recent buffer
+// served to the player, not to what's being heard right at that moment.
+type SpectrumTap struct {
+	mutex sync.Mutex // protects magnitudes only
+	source io.Reader
+	magnitudes []float64
+
+	// only accessed from Read
+	history []float64 // circular buffer of the most recent mono samples
+	historyIndex int
+	window []float64
+	fftRe []float64
+	fftIm []float64
+}
+
+// Creates a new [SpectrumTap]. The fftSize must be a power of 2, at least
+// 2. Common values are between 512 and 4096: bigger sizes give more
+// frequency resolution but react slower to changes. This method will
+// panic if the fftSize is invalid.
+func NewSpectrumTap(source io.Reader, fftSize int) *SpectrumTap {
+	if fftSize < 2 || fftSize & (fftSize - 1) != 0 {
+		panic("NewSpectrumTap fftSize must be a power of 2, at least 2")
+	}
+	window := make([]float64, fftSize)
+	for i := range window {
+		window[i] = 0.5 - 0.5*math.Cos(2*math.Pi*float64(i)/float64(fftSize))
+	}
+	return &SpectrumTap {
+		source: source,
+		magnitudes: make([]float64, fftSize/2 + 1),
+		history: make([]float64, fftSize),
+		window: window,
+		fftRe: make([]float64, fftSize),
+		fftIm: make([]float64, fftSize),
+	}
+}
+
+// Returns the underlying source.
+func (self *SpectrumTap) Source() io.Reader {
+	return self.source
+}
+
+// Returns a copy of the magnitudes of the most recent spectrum, with
+// fftSize/2 + 1 bins going from 0Hz to sampleRate/2. The frequency of
+// bin i is i*sampleRate/fftSize. Magnitudes are normalized so a sine
+// of amplitude A centered on a bin has a magnitude close to A.
+func (self *SpectrumTap) Magnitudes() []float64 {
+	self.mutex.Lock()
+	magnitudes := make([]float64, len(self.magnitudes))
+	copy(magnitudes, self.magnitudes)
+	self.mutex.Unlock()
+	return magnitudes
+}
+
+// Implements [io.Reader]. The returned read length will always be
+// multiple of 4, aligning to Ebitengine's sample size.
+func (self *SpectrumTap) Read(buffer []byte) (int, error) {
+	n, err := readFrames(self.source, buffer)
+	if n == 0 { return n, err }
+
+	// push new samples into the history
+	for i := 0; i < n; i += 4 {
+		left, right := GetSampleAsF64(buffer[i : ])
+		self.history[self.historyIndex] = (left + right)/2
+		self.historyIndex = (self.historyIndex + 1) & (len(self.history) - 1)
+	}
+
+	// apply the window to the history, from oldest to newest
+	size := len(self.history)
+	for i := 0; i < size; i++ {
+		self.fftRe[i] = self.history[(self.historyIndex + i) & (size - 1)]*self.window[i]
+		self.fftIm[i] = 0
+	}
+	fftInPlace(self.fftRe, self.fftIm)
+
+	// publish magnitudes. the hann window halves the amplitude, and
+	// the energy of real signals is split between two bins
+	scale := 4.0/float64(size)
+	self.mutex.Lock()
+	for i := range self.magnitudes {
+		self.magnitudes[i] = math.Hypot(self.fftRe[i], self.fftIm[i])*scale
+	}
+	self.magnitudes[0] /= 2
+	self.magnitudes[size/2] /= 2
+	self.mutex.Unlock()
+	return n, err
+}
+
+// Implements [io.Seeker]. The spectrum history is preserved.
+//
+// If the underlying source doesn't implement [io.Seeker], [ErrNotSeekable]
+// is returned.
+func (self *SpectrumTap) Seek(offset int64, whence int) (int64, error) {
+	return seekSource(self.source, offset, whence)
+}
+
+// Iterative radix-2 FFT. len(re) must be a power of 2 and equal to len(im).
+func fftInPlace(re, im []float64) {
+	size := len(re)
+	if size < 2 { return }
+
+	// bit reversal permutation
+	shift := 64 - bits.TrailingZeros(uint(size))
+	for i := 0; i < size; i++ {
+		j := int(bits.Reverse64(uint64(i)) >> shift)
+		if j > i {
+			re[i], re[j] = re[j], re[i]
+			im[i], im[j] = im[j], im[i]
+		}
+	}
+
+	// butterflies
+	for half := 1; half < size; half <<= 1 {
+		angle := -math.Pi/float64(half)
+		stepRe, stepIm := math.Cos(angle), math.Sin(angle)
+		for start := 0; start < size; start += half << 1 {
+			wRe, wIm := 1.0, 0.0
+			for k := start; k < start + half; k++ {
+				tRe := wRe*re[k + half] - wIm*im[k + half]
+				tIm := wRe*im[k + half] + wIm*re[k + half]
+				re[k + half], im[k + half] = re[k] - tRe, im[k] - tIm
+				re[k], im[k] = re[k] + tRe, im[k] + tIm
+				wRe, wIm = wRe*stepRe - wIm*stepIm, wRe*stepIm + wIm*stepRe
+			}
+		}
+	}
+}
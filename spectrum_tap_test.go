@@ -0,0 +1,30 @@
+package edau
+
+import "io"
+import "math"
+import "bytes"
+import "testing"
+
+func TestSpectrumTap(t *testing.T) {
+	// a sine centered on bin 32 of a 1024 FFT
+	const fftSize = 1024
+	freq := 32*float64(testSampleRate)/fftSize
+	input := testSineL16(freq, 0.5, testSampleRate)
+	tap := NewSpectrumTap(bytes.NewReader(input), fftSize)
+	output := make([]byte, 4096*4)
+	_, err := io.ReadFull(tap, output)
+	if err != nil { t.Fatal(err) }
+	if !bytes.Equal(output, input[0 : len(output)]) { t.Fatal("expected audio to pass through unchanged") }
+
+	magnitudes := tap.Magnitudes()
+	if len(magnitudes) != fftSize/2 + 1 {
+		t.Fatalf("expected %d magnitudes, got %d", fftSize/2 + 1, len(magnitudes))
+	}
+	if math.Abs(magnitudes[32] - 0.5) > 0.01 {
+		t.Fatalf("expected magnitude 0.5 at bin 32, got %f", magnitudes[32])
+	}
+	for i, magnitude := range magnitudes {
+		if i >= 31 && i <= 33 { continue } // the hann window spreads into neighbouring bins
+		if magnitude > 0.001 { t.Fatalf("unexpected magnitude %f at bin %d", magnitude, i) }
+	}
+}
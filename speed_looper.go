@@ -0,0 +1,102 @@
+package edau
+
+import "io"
+
+// A SpeedLooper combines a [Looper] and a [SpeedShifter] in order to loop
+// a region of an audio stream while playing it at an adjustable speed.
+//
+// The key is the order of composition: the looper is placed before the
+// speed shifter, so the loop transitions happen on the source side and the
+// shifter only sees a continuous stream. This means that the interpolation
+// lookahead crosses the loop boundary naturally, reading the samples from
+// the loop start right after those before the loop end, and the loop
+// transitions remain seamless under any speed changes. No seeks are ever
+// performed on the shifter during regular playback, so its interpolation
+// window is never reset.
+//
+// Explicit seeks, on the other hand, still reset the interpolation window,
+// as explained in [SpeedShifter.Seek].
+type SpeedLooper struct {
+	looper *Looper
+	shifter *SpeedShifter
+}
+
+// Creates a new [SpeedLooper]. The stream and loop points follow the same
+// rules as in [NewLooper], and the speed shifter is created as in
+// [NewDefaultSpeedShifter]. This method will panic if the loop points are
+// invalid.
+func NewSpeedLooper(stream io.ReadSeeker, loopStart int64, loopEnd int64) *SpeedLooper {
+	looper := NewLooper(stream, loopStart, loopEnd)
+	return &SpeedLooper {
+		looper: looper,
+		shifter: NewDefaultSpeedShifter(looper),
+	}
+}
+
+// Implements [io.Reader]. The returned read length will always be
+// multiple of 4, aligning to Ebitengine's sample size.
+func (self *SpeedLooper) Read(buffer []byte) (int, error) {
+	return self.shifter.Read(buffer)
+}
+
+// Implements [io.Seeker], with the same limitations as [SpeedShifter.Seek].
+// Seeks are performed on the underlying stream, so it's the caller's
+// responsibility to make sure the seek falls inside the loop (if that's
+// desired).
+func (self *SpeedLooper) Seek(offset int64, whence int) (int64, error) {
+	if whence == io.SeekCurrent && offset == 0 {
+		return self.GetPosition(), nil
+	}
+	return self.shifter.Seek(offset, whence)
+}
+
+// Returns the currently configured playback speed.
+func (self *SpeedLooper) Speed() float64 {
+	return self.shifter.Speed()
+}
+
+// Sets the playback speed. See [SpeedShifter.SetSpeed] for further details.
+func (self *SpeedLooper) SetSpeed(speed float64) {
+	self.shifter.SetSpeed(speed)
+}
+
+// Sets the minimum and maximum playback speeds. See
+// [SpeedShifter.SetSpeedLimits] for further details.
+func (self *SpeedLooper) SetSpeedLimits(minSpeed, maxSpeed float64) {
+	self.shifter.SetSpeedLimits(minSpeed, maxSpeed)
+}
+
+// Returns the currently configured loop starting and ending points.
+func (self *SpeedLooper) GetLoopPoints() (int64, int64) {
+	return self.looper.GetLoopPoints()
+}
+
+// Sets new values for the loop starting and ending points. See
+// [Looper.AdjustLoop] for further details. Notice that the speed shifter
+// reads slightly ahead of the playback position, so changes to the loop
+// points take effect with a small delay (see [SpeedShifter.Latency]).
+func (self *SpeedLooper) AdjustLoop(loopStart, loopEnd int64) {
+	self.looper.AdjustLoop(loopStart, loopEnd)
+}
+
+// Returns the current playback position, in bytes, on the underlying stream.
+// Unlike [Looper.GetPosition], the position accounts for the data that the
+// speed shifter has read ahead but not served yet, so it's aligned with the
+// audio that's actually being served. The value will always be multiple of 4.
+func (self *SpeedLooper) GetPosition() int64 {
+	// lock order must match reads: shifter first, looper second
+	self.shifter.mutex.Lock()
+	defer self.shifter.mutex.Unlock()
+	pending := int64(self.shifter.lookaheadBytes + self.shifter.leftoverBytes)
+	pending -= pending & 0b11
+
+	self.looper.mutex.Lock()
+	defer self.looper.mutex.Unlock()
+	position := self.looper.position - pending
+	loopStart, loopEnd := self.looper.loopStart, self.looper.activeLoopEnd
+	if position < loopStart && self.looper.position >= loopStart {
+		position += loopEnd - loopStart // pending data from before the loop wrap
+	}
+	if position < 0 { position = 0 }
+	return position
+}
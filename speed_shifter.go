@@ -8,6 +8,10 @@ package edau
 import "io"
 import "math"
 import "sync"
+import "time"
+
+const defaultMinSpeed = 0.05
+const defaultMaxSpeed = 4.0
 
 // A SpeedShifter wraps an audio stream and allows playing it at a different
 // speed than the original by resampling in real-time.
@@ -18,15 +22,21 @@ type SpeedShifter struct {
 	mutex sync.Mutex
 	source io.Reader
 	speed float64
+	minSpeed float64
+	maxSpeed float64
 	windowSize int
-	interpolator InterpolatorFunc
+	interpolator InterpolatorFunc // left channel interpolator
+	rightInterpolator InterpolatorFunc
 
 	fracPos float64
 	leftoverBytes  int // from previous reads, not consumed yet
 	lookaheadBytes int // lookahead bytes ready for interpolation
+	realFrames int // source frames (not padding) in the window from the center onwards
+	sourceEOF bool // once reached, the window is flushed with zero padding
+	normalized bool // whether windows hold [-1, 1] values instead of [-32768, 32767]
 	
-	leftWindow  circularWindow
-	rightWindow circularWindow
+	leftWindow  SlidingWindow
+	rightWindow SlidingWindow
 	auxReadBuffer []byte
 }
 
@@ -43,8 +53,30 @@ func NewDefaultSpeedShifter(source io.Reader) *SpeedShifter {
 // you do not want to worry about interpolators and resampling, see [NewDefaultSpeedShifter]
 // instead.
 //
-// The interpolator's windowSize must be multiple of 2.
+// The interpolator's windowSize must be multiple of 2. The speed is clamped to the
+// default speed limits, see [SpeedShifter.SetSpeedLimits].
 func NewSpeedShifter(source io.Reader, speed float64, windowSize int, interpolator InterpolatorFunc) *SpeedShifter {
+	return NewSpeedShifterWithCapacity(source, speed, windowSize, interpolator, 8)
+}
+
+// Like [NewSpeedShifter], but with a custom capacity for the internal
+// interpolation window buffers. Each channel uses a buffer of windowSize*
+// bufferMultiplier float64 values (the default multiplier is 8). Samples are
+// appended to the buffers until they are full, and then the current window
+// is copied back to the start, so the multiplier trades memory for the
+// frequency of those copies: with a multiplier m, a copy of windowSize values
+// happens every windowSize*(m - 1) source samples. A multiplier of 1 is
+// allowed, but makes every sample require a copy.
+//
+// The multiplier doesn't affect how often or how much the underlying source
+// is read: each Read reads approximately as many samples from the source as
+// required to fill the given buffer at the current speed, so the size of the
+// source reads (and of the auxiliary read buffer, which grows to fit the
+// biggest read) is controlled by the size of the buffers passed to Read.
+//
+// The bufferMultiplier must be at least 1. This method will panic otherwise,
+// or if the other parameters are invalid as in NewSpeedShifter.
+func NewSpeedShifterWithCapacity(source io.Reader, speed float64, windowSize int, interpolator InterpolatorFunc, bufferMultiplier int) *SpeedShifter {
 	if windowSize < 2 {
 		panic("NewSpeedShifter windowSize must be at least 2")
 	}
@@ -53,17 +85,23 @@ func NewSpeedShifter(source io.Reader, speed float64, windowSize int, interpolat
 		// Note: odd window sizes require interpolating around [center - 0.5, center + 0.5],
 		//       so it's a bit trickier than even sizes, and the reason I didn't add it yet
 	}
+	if bufferMultiplier < 1 {
+		panic("NewSpeedShifterWithCapacity bufferMultiplier must be at least 1")
+	}
 
 	const numChannels = 2
-	bufferSize := windowSize*8
+	bufferSize := windowSize*bufferMultiplier
 	buffer := make([]float64, bufferSize*numChannels)
 	shifter := &SpeedShifter {
 		source: source,
-		speed: speed,
+		speed: clampSpeed(speed, defaultMinSpeed, defaultMaxSpeed),
+		minSpeed: defaultMinSpeed,
+		maxSpeed: defaultMaxSpeed,
 		windowSize: windowSize,
 		interpolator: interpolator,
-		leftWindow:  circularWindow{ winSize: windowSize, buffer: buffer[ : bufferSize] },
-		rightWindow: circularWindow{ winSize: windowSize, buffer: buffer[bufferSize : ] },
+		rightInterpolator: interpolator,
+		leftWindow:  SlidingWindow{ winSize: windowSize, buffer: buffer[ : bufferSize] },
+		rightWindow: SlidingWindow{ winSize: windowSize, buffer: buffer[bufferSize : ] },
 		auxReadBuffer: nil,
 	}
 	
@@ -71,6 +109,12 @@ func NewSpeedShifter(source io.Reader, speed float64, windowSize int, interpolat
 	return shifter
 }
 
+// Returns the underlying source, e.g. to close it or query its length
+// without having to keep a separate reference.
+func (self *SpeedShifter) Source() io.Reader {
+	return self.source
+}
+
 // Returns the currently configured playback speed.
 func (self *SpeedShifter) Speed() float64 {
 	self.mutex.Lock()
@@ -86,64 +130,181 @@ func (self *SpeedShifter) Speed() float64 {
 // when pushed below 50 milliseconds. It also depends a lot on how much processing
 // and effects you are adding to the audio.
 //
+// The speed is clamped to the limits configured with [SpeedShifter.SetSpeedLimits].
+//
 // [Player.SetBufferSize]: https://pkg.go.dev/github.com/hajimehoshi/ebiten/v2/audio#Player.SetBufferSize
 func (self *SpeedShifter) SetSpeed(speed float64) {
 	self.mutex.Lock()
-	self.speed = speed
+	self.speed = clampSpeed(speed, self.minSpeed, self.maxSpeed)
+	self.mutex.Unlock()
+}
+
+// Returns the currently configured minimum and maximum playback speeds.
+func (self *SpeedShifter) SpeedLimits() (float64, float64) {
+	self.mutex.Lock()
+	minSpeed, maxSpeed := self.minSpeed, self.maxSpeed
+	self.mutex.Unlock()
+	return minSpeed, maxSpeed
+}
+
+// Sets the minimum and maximum playback speeds that [SpeedShifter.SetSpeed]
+// will clamp to. The current speed is also clamped to the new limits. The
+// defaults are 0.05 and 4.0, which prevent huge reads on the underlying
+// source and near-zero speeds.
+//
+// The minimum must be strictly positive and smaller or equal to the maximum.
+// This method will panic otherwise.
+func (self *SpeedShifter) SetSpeedLimits(minSpeed, maxSpeed float64) {
+	if minSpeed <= 0 { panic("SetSpeedLimits minSpeed must be strictly positive") }
+	if minSpeed > maxSpeed { panic("SetSpeedLimits minSpeed must be <= maxSpeed") }
+	self.mutex.Lock()
+	self.minSpeed, self.maxSpeed = minSpeed, maxSpeed
+	self.speed = clampSpeed(self.speed, minSpeed, maxSpeed)
+	self.mutex.Unlock()
+}
+
+// Returns whether the underlying source has reached [io.EOF] and all the
+// buffered samples have already been served. Once drained, reads will keep
+// returning [io.EOF] until the shifter is seeked, so it's safe to discard
+// or reset it.
+func (self *SpeedShifter) Drained() bool {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	return self.sourceEOF && self.realFrames <= 0
+}
+
+// Sets separate interpolators for the left and right channels, which can be
+// useful for creative effects, like decorrelating the channels. By default,
+// both channels use the interpolator given on creation. The interpolators
+// must work with the window size given on creation, and they can't be nil.
+// This method will panic otherwise.
+func (self *SpeedShifter) SetChannelInterpolators(left, right InterpolatorFunc) {
+	if left == nil || right == nil { panic("SetChannelInterpolators interpolators can't be nil") }
+	self.mutex.Lock()
+	self.interpolator, self.rightInterpolator = left, right
 	self.mutex.Unlock()
 }
 
+// Sets whether the interpolation windows hold normalized [-1, 1] values
+// instead of raw [-32768, 32767] values. This is mostly relevant for custom
+// interpolators that expect normalized values, like the ones that wrap other
+// normalized float effects. The default is false, which is a bit faster.
+//
+// Changing the mode rescales the values already in the windows, so it can
+// be done at any point without causing discontinuities.
+func (self *SpeedShifter) SetNormalized(normalized bool) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	if self.normalized == normalized { return }
+	self.normalized = normalized
+	rescaleWindow(self.leftWindow.Get(), normalized)
+	rescaleWindow(self.rightWindow.Get(), normalized)
+}
+
+// Returns whether the interpolation windows hold normalized values.
+// See [SpeedShifter.SetNormalized].
+func (self *SpeedShifter) Normalized() bool {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	return self.normalized
+}
+
+// Returns the current delay between a sample being read from the underlying
+// source and the corresponding resampled audio being served. The latency
+// comes from the interpolation lookahead and the source bytes that have been
+// read but not consumed yet, and it's expressed in output time, so it
+// depends on the current playback speed.
+//
+// The sampleRate must be strictly positive. This method will panic otherwise.
+func (self *SpeedShifter) Latency(sampleRate int) time.Duration {
+	if sampleRate <= 0 { panic("Latency sampleRate must be strictly positive") }
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	frames := float64(self.lookaheadBytes/4) + float64(self.leftoverBytes/4) - self.fracPos
+	if frames < 0 { frames = 0 }
+	if self.speed > 0 { frames /= self.speed }
+	return time.Duration((frames*float64(time.Second))/float64(sampleRate))
+}
+
 // Implements [io.Reader]. This function will always try to fill the buffer as much
 // as possible, even if this requires multiple reads on the underlying stream.
 //
 // The returned read length will always also be multiple of 4, aligning to Ebitengine's
 // sample size.
+//
+// When the underlying source reaches [io.EOF], the samples remaining in the interpolation
+// window are still served before returning [io.EOF]. Trailing bytes that don't form a whole
+// sample are discarded.
 func (self *SpeedShifter) Read(buffer []byte) (int, error) {
 	// do not read incomplete samples (always read a number of bytes multiple of 4)
 	buffer = buffer[0 : len(buffer) - (len(buffer) & 0b11)]
 
 	// keep reading until an error happens or we fill the buffer
-	bytesServed := 0
-	for bytesServed < len(buffer) {
+	output := shifterOutput{ bytes: buffer }
+	framesServed := 0
+	for framesServed < output.frames() {
 		// single read from underlying buffer
-		n, err := self.singleRead(buffer[bytesServed : ])
-		bytesServed += n
-		if err != nil { return bytesServed, err }
+		n, err := self.singleRead(output.from(framesServed))
+		framesServed += n
+		if err != nil { return framesServed << 2, err }
 	}
 
-	return bytesServed, nil
+	return framesServed << 2, nil
+}
+
+// Like [SpeedShifter.Read], but the samples are stored directly as
+// normalized float64 values in the given slices, skipping the conversion
+// to L16. This is more efficient when the output is going to be processed
+// further as floats, like when chaining custom effects. Values are clamped
+// to [-1, 1] in the same way Read would do. Returns the number of samples
+// written to each slice, which can't exceed the smaller slice length.
+func (self *SpeedShifter) ReadFloat64(left, right []float64) (int, error) {
+	if len(left) > len(right) { left = left[0 : len(right)] }
+	if len(right) > len(left) { right = right[0 : len(left)] }
+
+	// keep reading until an error happens or we fill the buffers
+	output := shifterOutput{ left: left, right: right }
+	framesServed := 0
+	for framesServed < output.frames() {
+		n, err := self.singleRead(output.from(framesServed))
+		framesServed += n
+		if err != nil { return framesServed, err }
+	}
+
+	return framesServed, nil
 }
 
 // Like Read, but only reads once from the underlying source. If the given
-// buffer can't be filled, this method doesn't retry, it simply returns what
-// it got.
-func (self *SpeedShifter) singleRead(buffer []byte) (int, error) {
+// output can't be filled, this method doesn't retry, it simply returns what
+// it got. The returned value is the number of frames served.
+func (self *SpeedShifter) singleRead(output shifterOutput) (int, error) {
 	self.mutex.Lock()
 	defer self.mutex.Unlock()
 
-	// base case
-	if len(buffer) == 0 { return 0, nil }
+	// base cases
+	numFrames := output.frames()
+	if numFrames == 0 { return 0, nil }
+	if self.sourceEOF { return self.flush(output) }
 
 	// general case
 	requiredLookahead := (self.windowSize << 1) // *4/2
 	pendingLookahead  := requiredLookahead - self.lookaheadBytes
 	readCompensation  := pendingLookahead  - self.leftoverBytes
-	samplesRequired   := math.Ceil((float64(len(buffer))*self.speed)/4.0) // ceil needs to be applied on samples
+	samplesRequired   := math.Ceil(float64(numFrames)*self.speed) // ceil needs to be applied on samples
 	bytesToRead := int(samplesRequired*4.0 + float64(readCompensation))
-	if bytesToRead <= 0 { panic("unexpected situation") }
+	if bytesToRead < 0 { bytesToRead = 0 } // leftovers already cover the request
 
-	// acquire aux buffer for reading
+	// acquire aux buffer for reading. the buffer grows geometrically and
+	// never shrinks, so steady-state reads don't allocate
 	minReadBufferSize := self.leftoverBytes + bytesToRead
-	var readBuffer []byte
-	if len(self.auxReadBuffer) < minReadBufferSize {
-		if cap(self.auxReadBuffer) >= minReadBufferSize {
-			readBuffer = self.auxReadBuffer[0 : minReadBufferSize]
-		} else {
-			readBuffer = make([]byte, minReadBufferSize)
-		}
-	} else {
-		readBuffer = self.auxReadBuffer[0 : minReadBufferSize]
+	readBuffer := self.auxReadBuffer
+	if cap(readBuffer) < minReadBufferSize {
+		newCapacity := cap(readBuffer)*2
+		if newCapacity < minReadBufferSize { newCapacity = minReadBufferSize }
+		readBuffer = make([]byte, newCapacity)
 	}
+	readBuffer = readBuffer[0 : minReadBufferSize]
 
 	// copy leftoverBytes to the start of the buffer
 	if self.leftoverBytes > 0 {
@@ -166,33 +327,31 @@ func (self *SpeedShifter) singleRead(buffer []byte) (int, error) {
 	for self.lookaheadBytes < (self.windowSize << 1) {
 		if len(readBuffer) < 4 {
 			self.leftoverBytes = len(readBuffer)
+			if err == io.EOF { return self.startFlush(output) }
 			return 0, err
 		}
-		left, right := GetSampleAsI16(readBuffer)
-		self.leftWindow.Push(float64(left))
-		self.rightWindow.Push(float64(right))
+		self.pushFrame(readBuffer)
 		self.lookaheadBytes += 4
+		self.realFrames += 1
 		readBuffer = readBuffer[4 : ]
 	}
 
 	// process the bytes
-	bytesServed := 0
+	framesServed := 0
 	interpPosBase := float64(self.windowSize/2 - 1)
-	for len(readBuffer) >= 4 && bytesServed < len(buffer) {
+	for len(readBuffer) >= 4 && framesServed < numFrames {
 		// add sample
 		if self.fracPos < 1.0 {
 			left  := self.interpolator(self.leftWindow.Get(),  interpPosBase + self.fracPos)
-			right := self.interpolator(self.rightWindow.Get(), interpPosBase + self.fracPos)
-			StoreF64SampleAsL16(buffer[bytesServed : ], left, right)
-			bytesServed += 4
+			right := self.rightInterpolator(self.rightWindow.Get(), interpPosBase + self.fracPos)
+			self.storeFrame(output, framesServed, left, right)
+			framesServed += 1
 			self.fracPos += self.speed
 		}
 
 		// advance position
 		for self.fracPos >= 1.0 && len(readBuffer) >= 4 {
-			left, right := GetSampleAsI16(readBuffer)
-			self.leftWindow.Push(float64(left))
-			self.rightWindow.Push(float64(right))
+			self.pushFrame(readBuffer)
 			readBuffer = readBuffer[4 : ]
 			self.fracPos -= 1.0
 		}
@@ -206,8 +365,53 @@ func (self *SpeedShifter) singleRead(buffer []byte) (int, error) {
 	self.leftoverBytes = len(readBuffer)
 	if self.leftoverBytes < 0 { panic("unexpected situation") }
 
-	// return
-	return bytesServed, err
+	// on EOF, the remaining frames still need to go through the interpolator
+	if err == io.EOF {
+		n, err := self.startFlush(output.from(framesServed))
+		return framesServed + n, err
+	}
+	return framesServed, err
+}
+
+// Called when the underlying source reaches EOF. Leftover bytes that don't
+// form a whole sample are discarded, the pending lookahead is filled with
+// zero padding, and the flushing of the window starts.
+func (self *SpeedShifter) startFlush(output shifterOutput) (int, error) {
+	self.sourceEOF = true
+	self.leftoverBytes = 0
+	for self.lookaheadBytes < (self.windowSize << 1) {
+		self.leftWindow.Push(0)
+		self.rightWindow.Push(0)
+		self.lookaheadBytes += 4
+	}
+	return self.flush(output)
+}
+
+// Serves the samples that remain in the interpolation window after the
+// underlying source has reached EOF, advancing with zero padding until all
+// the source frames have gone through the interpolation center. Returns
+// [io.EOF] once all the samples have been served.
+func (self *SpeedShifter) flush(output shifterOutput) (int, error) {
+	framesServed := 0
+	interpPosBase := float64(self.windowSize/2 - 1)
+	for {
+		// advance position
+		for self.fracPos >= 1.0 && self.realFrames > 0 {
+			self.leftWindow.Push(0)
+			self.rightWindow.Push(0)
+			self.realFrames -= 1
+			self.fracPos -= 1.0
+		}
+		if self.realFrames <= 0 { return framesServed, io.EOF }
+		if framesServed >= output.frames() { return framesServed, nil }
+
+		// add sample
+		left  := self.interpolator(self.leftWindow.Get(),  interpPosBase + self.fracPos)
+		right := self.rightInterpolator(self.rightWindow.Get(), interpPosBase + self.fracPos)
+		self.storeFrame(output, framesServed, left, right)
+		framesServed += 1
+		self.fracPos += self.speed
+	}
 }
 
 // Implements [io.Seeker], with the limitation that io.SeekCurrent seeks
@@ -215,13 +419,12 @@ func (self *SpeedShifter) singleRead(buffer []byte) (int, error) {
 // used to get the current playback position).
 //
 // You may use Seek to rewind and start playing after stoping, but not to loop
-// or do seamless seeking with the resampled stream itself. Seamless seeking
-// could only be done correctly if notifying the seek in advance of the
-// interpolation window. That's not something anyone sane wants to figure out, so
-// seeking will seek on the underlying buffer but reset the internal interpolation
-// window of the speed shifter.
+// or do seamless seeking with the resampled stream itself. Seeking will seek on
+// the underlying buffer and reset the internal interpolation window of the speed
+// shifter. For seamless seeks, see [SpeedShifter.SeekSeamless] instead.
 //
-// This method panics if the underlying source doesn't implement [io.Seeker].
+// If the underlying source doesn't implement [io.Seeker], [ErrNotSeekable]
+// is returned.
 func (self *SpeedShifter) Seek(offset int64, whence int) (int64, error) {
 	self.mutex.Lock()
 	defer self.mutex.Unlock()
@@ -232,8 +435,8 @@ func (self *SpeedShifter) Seek(offset int64, whence int) (int64, error) {
 	}
 
 	// seek underlying source
-	seeker := self.source.(io.Seeker)
-	position, err := seeker.Seek(offset, whence)
+	position, err := seekSource(self.source, offset, whence)
+	if err == ErrNotSeekable { return position, err }
 
 	// Resets interpolation window and related state.
 	self.internalReset()
@@ -242,66 +445,141 @@ func (self *SpeedShifter) Seek(offset int64, whence int) (int64, error) {
 	return position, err
 }
 
+// Like [SpeedShifter.Seek], but instead of resetting the interpolation window,
+// the window keeps the samples up to its center and only the lookahead samples
+// are discarded and read again from the post-seek position. This way, the
+// interpolation continues across the seek boundary without glitches, which
+// makes it possible to loop resampled sounds seamlessly.
+//
+// The seek takes effect right after the sample currently at the interpolation
+// center, so the source samples that had already been read ahead are never
+// served. For tight loops, the seek should be issued when the position of the
+// underlying source minus [SpeedShifter.Latency] reaches the loop end.
+//
+// Like Seek, io.SeekCurrent is only supported with an offset of 0, and
+// [ErrNotSeekable] is returned if the underlying source doesn't implement
+// [io.Seeker]. If the underlying seek fails, the state of the speed shifter
+// is left unmodified.
+func (self *SpeedShifter) SeekSeamless(offset int64, whence int) (int64, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	if whence == io.SeekCurrent {
+		if offset == 0 { return 0, nil }
+		panic("can't use relative seeks on a SpeedShifter (due to lookaheads)")
+	}
+
+	// seek underlying source
+	position, err := seekSource(self.source, offset, whence)
+	if err != nil { return position, err }
+
+	// discard the lookahead and leftovers, which will be refilled from
+	// the new position on the next read. the samples up to the center
+	// are kept, so the interpolation doesn't see a discontinuity
+	lookaheadFrames := self.lookaheadBytes >> 2
+	self.leftWindow.discardNewest(lookaheadFrames)
+	self.rightWindow.discardNewest(lookaheadFrames)
+	self.lookaheadBytes = 0
+	self.leftoverBytes  = 0
+	if self.realFrames > 1 { self.realFrames = 1 } // only the center remains
+	self.sourceEOF = false
+
+	return position, nil
+}
+
 // Resets the interpolation window and related state.
 func (self *SpeedShifter) internalReset() {
 	self.leftoverBytes  = 0
 	self.lookaheadBytes = 0
+	self.realFrames = 0
+	self.sourceEOF  = false
 	self.leftWindow.Reset()
 	self.rightWindow.Reset()
-	for i := 0; i < self.windowSize/2 - 1; i++ {
-		self.leftWindow.Push(0)
-		self.rightWindow.Push(0)
-	}
-	
+
+	// the window is primed by repeating the first sample up to the
+	// interpolation center. priming with zeros instead would make the
+	// interpolators ramp up from silence at the start and after seeks
 	buffer := []byte{0, 0, 0, 0}
-	n, _ := self.source.Read(buffer)
-	if n == 4 {
+	n, _ := io.ReadFull(self.source, buffer)
+	if n == 4 { self.realFrames = 1 }
+	for i := 0; i < self.windowSize/2; i++ {
+		self.pushFrame(buffer)
+	}
+}
+
+// Pushes the first sample of the given buffer into the interpolation
+// windows, normalized or not depending on the current mode.
+func (self *SpeedShifter) pushFrame(buffer []byte) {
+	if self.normalized {
 		left, right := GetSampleAsF64(buffer)
 		self.leftWindow.Push(left)
 		self.rightWindow.Push(right)
 	} else {
-		self.leftWindow.Push(0)
-		self.rightWindow.Push(0)
+		left, right := GetSampleAsI16(buffer)
+		self.leftWindow.Push(float64(left))
+		self.rightWindow.Push(float64(right))
 	}
 }
 
-// --- helper circularWindow type for efficient interpolation ---
+// Stores an interpolated sample at the given frame index of the output. The
+// sample is interpreted as normalized or not depending on the current mode.
+func (self *SpeedShifter) storeFrame(output shifterOutput, index int, left, right float64) {
+	if output.bytes == nil {
+		if self.normalized {
+			output.left[index], output.right[index] = clampUnit(left), clampUnit(right)
+		} else {
+			output.left[index], output.right[index] = NormalizeF64(left), NormalizeF64(right)
+		}
+	} else if self.normalized {
+		StoreNormF64SampleAsL16(output.bytes[index << 2 : ], left, right)
+	} else {
+		StoreF64SampleAsL16(output.bytes[index << 2 : ], left, right)
+	}
+}
 
-type circularWindow struct {
-	winSize int
-	buffer []float64
-	startIndex int
-	endIndex int
+// Destination for the interpolated samples of a [SpeedShifter]: either
+// a L16 buffer for Read, or left and right float64 slices for ReadFloat64.
+type shifterOutput struct {
+	bytes []byte // nil for float64 outputs
+	left  []float64
+	right []float64
 }
 
-func (self *circularWindow) Reset() {
-	self.startIndex = 0
-	self.endIndex   = 0
+// Returns the number of frames that fit in the output.
+func (self shifterOutput) frames() int {
+	if self.bytes == nil { return len(self.left) }
+	return len(self.bytes) >> 2
 }
 
-func (self *circularWindow) Get() []float64 {
-	return self.buffer[self.startIndex : self.endIndex]
+// Returns the output starting at the given frame index.
+func (self shifterOutput) from(index int) shifterOutput {
+	if self.bytes == nil {
+		return shifterOutput{ left: self.left[index : ], right: self.right[index : ] }
+	}
+	return shifterOutput{ bytes: self.bytes[index << 2 : ] }
 }
 
-func (self *circularWindow) Push(value float64) {
-	if self.endIndex < len(self.buffer) {
-		self.buffer[self.endIndex] = value
-		if self.winSize == self.endIndex - self.startIndex { // window is full
-			self.startIndex += 1
-		}
-		self.endIndex += 1
-	} else {
-		if self.winSize == self.endIndex - self.startIndex { // window is full
-			copy(self.buffer, self.buffer[self.startIndex + 1 : self.endIndex])
-			self.buffer[self.winSize - 1] = value
-			self.startIndex = 0
-			self.endIndex   = self.winSize
+func clampUnit(value float64) float64 {
+	if value >  1.0 { return  1.0 }
+	if value < -1.0 { return -1.0 }
+	return value
+}
+
+// Converts window values between the [-32768, 32767] and [-1, 1] ranges.
+func rescaleWindow(values []float64, toNormalized bool) {
+	for i, value := range values {
+		if toNormalized {
+			values[i] = NormalizeF64(value)
+		} else if value >= 0 {
+			values[i] = value*32767.0
 		} else {
-			copy(self.buffer, self.buffer[self.startIndex : self.endIndex])
-			newIndex := self.endIndex - self.startIndex
-			self.buffer[newIndex] = value
-			self.startIndex = 0
-			self.endIndex   = newIndex + 1
+			values[i] = value*32768.0
 		}
 	}
 }
+
+func clampSpeed(speed, minSpeed, maxSpeed float64) float64 {
+	if speed < minSpeed { return minSpeed }
+	if speed > maxSpeed { return maxSpeed }
+	return speed
+}
@@ -0,0 +1,317 @@
+package edau
+
+import "io"
+import "math"
+import "bytes"
+import "testing"
+
+func BenchmarkSpeedShifterSweep(b *testing.B) {
+	source := &testLoopingSource{ data: testSineL16(440, 0.5, testSampleRate) }
+	shifter := NewDefaultSpeedShifter(source)
+	buffer := make([]byte, 4096)
+	sweep := func() {
+		for speed := 0.1; speed <= 4.0; speed += 0.1 {
+			shifter.SetSpeed(speed)
+			_, err := shifter.Read(buffer)
+			if err != nil { b.Fatal(err) }
+		}
+	}
+
+	// warm up so the aux buffer reaches its final size, then
+	// ensure that steady-state reads don't allocate
+	sweep()
+	allocs := testing.AllocsPerRun(10, sweep)
+	if allocs > 0 { b.Fatalf("expected zero allocations per sweep, got %.1f", allocs) }
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sweep()
+	}
+}
+
+// Reading L16 samples and converting them to floats for further processing,
+// compared with BenchmarkSpeedShifterChainFloat64.
+func BenchmarkSpeedShifterChainL16(b *testing.B) {
+	source := &testLoopingSource{ data: testSineL16(440, 0.5, testSampleRate) }
+	shifter := NewDefaultSpeedShifter(source)
+	shifter.SetSpeed(1.3)
+	buffer := make([]byte, 4096)
+	left, right := make([]float64, 1024), make([]float64, 1024)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := shifter.Read(buffer)
+		if err != nil { b.Fatal(err) }
+		for j := range left {
+			left[j], right[j] = GetSampleAsF64(buffer[j*4 : ])
+		}
+	}
+}
+
+func BenchmarkSpeedShifterChainFloat64(b *testing.B) {
+	source := &testLoopingSource{ data: testSineL16(440, 0.5, testSampleRate) }
+	shifter := NewDefaultSpeedShifter(source)
+	shifter.SetSpeed(1.3)
+	left, right := make([]float64, 1024), make([]float64, 1024)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := shifter.ReadFloat64(left, right)
+		if err != nil { b.Fatal(err) }
+	}
+}
+
+// A source that loops its data endlessly without allocating.
+type testLoopingSource struct {
+	data []byte
+	position int
+}
+
+func (self *testLoopingSource) Read(buffer []byte) (int, error) {
+	n := copy(buffer, self.data[self.position : ])
+	self.position += n
+	if self.position == len(self.data) { self.position = 0 }
+	return n, nil
+}
+
+func TestSpeedShifterOutputLength(t *testing.T) {
+	const inputFrames = 1000
+	input := make([]byte, inputFrames*4)
+	for i := 0; i < len(input); i += 4 {
+		StoreNormF64SampleAsL16(input[i : ], 0.5, -0.5)
+	}
+
+	// speeds are exactly representable so the expected
+	// lengths don't depend on rounding errors
+	for _, speed := range []float64{ 0.25, 0.5, 0.75, 1.0, 1.25, 1.5, 2.0, 3.0 } {
+		for _, readSize := range []int{ 4, 64, 1000, 16384 } {
+			shifter := NewDefaultSpeedShifter(bytes.NewReader(input))
+			shifter.SetSpeed(speed)
+			outputFrames := 0
+			buffer := make([]byte, readSize)
+			for {
+				n, err := shifter.Read(buffer)
+				outputFrames += n/4
+				if err == io.EOF { break }
+				if err != nil { t.Fatal(err) }
+				if shifter.Drained() { t.Fatalf("speed %.2f, read size %d: drained before EOF", speed, readSize) }
+			}
+			if !shifter.Drained() { t.Fatalf("speed %.2f, read size %d: expected drained after EOF", speed, readSize) }
+
+			expected := int(math.Ceil(inputFrames/speed))
+			if outputFrames != expected {
+				t.Fatalf("speed %.2f, read size %d: expected %d output frames, got %d", speed, readSize, expected, outputFrames)
+			}
+		}
+	}
+}
+
+func TestSpeedShifterNormalized(t *testing.T) {
+	// a normalizing interpolator that would clip badly on raw values
+	var sawOutOfRange bool
+	interp := func(window []float64, pos float64) float64 {
+		for _, value := range window {
+			if value < -1.0 || value > 1.0 { sawOutOfRange = true }
+		}
+		return InterpHermite6Pt3Ord(window, pos)
+	}
+
+	for _, speed := range []float64{0.75, 1.0, 1.3} {
+		source := testSineL16(440, 0.5, 2048)
+		raw := NewSpeedShifter(bytes.NewReader(source), speed, 6, InterpHermite6Pt3Ord)
+		norm := NewSpeedShifter(bytes.NewReader(source), speed, 6, interp)
+		norm.SetNormalized(true)
+		rawOut, err := io.ReadAll(raw)
+		if err != nil { t.Fatal(err) }
+		normOut, err := io.ReadAll(norm)
+		if err != nil { t.Fatal(err) }
+		if sawOutOfRange { t.Fatalf("speed %.2f: normalized window had values out of range", speed) }
+		if len(rawOut) != len(normOut) {
+			t.Fatalf("speed %.2f: expected %d bytes, got %d", speed, len(rawOut), len(normOut))
+		}
+		
+		// results may differ by rounding, but not more
+		for i := 0; i < len(rawOut); i += 4 {
+			l1, r1 := GetSampleAsI16(rawOut[i : ])
+			l2, r2 := GetSampleAsI16(normOut[i : ])
+			if absInt(int(l1) - int(l2)) > 1 || absInt(int(r1) - int(r2)) > 1 {
+				t.Fatalf("speed %.2f, frame %d: raw (%d, %d) vs normalized (%d, %d)", speed, i/4, l1, r1, l2, r2)
+			}
+		}
+	}
+}
+
+func TestSpeedShifterSeekSeamless(t *testing.T) {
+	// the loop contains a whole number of periods, so looping seamlessly
+	// must give the same results as resampling an endless source
+	const loopFrames = 1000
+	loop := testSineL16(100, 0.5, loopFrames)
+	reference := NewSpeedShifter(&testLoopingSource{ data: loop }, 0.8, 6, InterpHermite6Pt3Ord)
+
+	// the looped source has extra data after the loop end, as the
+	// lookahead will read past it before the seek is issued
+	source := bytes.NewReader(append(append([]byte{}, loop...), loop...))
+	shifter := NewSpeedShifter(source, 0.8, 6, InterpHermite6Pt3Ord)
+
+	seeks := 0
+	expected, got := make([]byte, 4), make([]byte, 4)
+	for i := 0; i < 3*loopFrames; i++ {
+		_, err := io.ReadFull(reference, expected)
+		if err != nil { t.Fatal(err) }
+		_, err = io.ReadFull(shifter, got)
+		if err != nil { t.Fatal(err) }
+		if !bytes.Equal(expected, got) {
+			t.Fatalf("frame %d (after %d seeks): expected %v, got %v", i, seeks, expected, got)
+		}
+
+		// seek when the interpolation center reaches the last loop frame
+		srcPosition, _ := source.Seek(0, io.SeekCurrent)
+		center := (srcPosition - int64(shifter.leftoverBytes + shifter.lookaheadBytes))/4 - 1
+		if center == loopFrames - 1 {
+			_, err := shifter.SeekSeamless(0, io.SeekStart)
+			if err != nil { t.Fatal(err) }
+			seeks += 1
+		}
+	}
+	if seeks < 2 { t.Fatalf("expected at least 2 seeks, got %d", seeks) }
+}
+
+func TestSpeedShifterPrimingDC(t *testing.T) {
+	const dc = 12000
+	source := make([]byte, 256*4)
+	for i := 0; i < len(source); i += 4 {
+		StoreL16Sample(source[i : ], dc, -dc)
+	}
+
+	for _, speed := range []float64{0.6, 1.0, 1.7} {
+		shifter := NewSpeedShifter(bytes.NewReader(source), speed, 6, InterpHermite6Pt3Ord)
+		for pass := 0; pass < 2; pass++ { // before and after seeking
+			output := make([]byte, 32*4)
+			_, err := io.ReadFull(shifter, output)
+			if err != nil { t.Fatal(err) }
+			for i := 0; i < len(output); i += 4 {
+				left, right := GetSampleAsI16(output[i : ])
+				if absInt(int(left) - dc) > 1 || absInt(int(right) + dc) > 1 {
+					t.Fatalf("speed %.1f, pass %d, frame %d: expected (%d, %d), got (%d, %d)", speed, pass, i/4, dc, -dc, left, right)
+				}
+			}
+			_, err = shifter.Seek(0, io.SeekStart)
+			if err != nil { t.Fatal(err) }
+		}
+	}
+}
+
+func TestSpeedShifterNotSeekable(t *testing.T) {
+	source := struct{ io.Reader }{ bytes.NewReader(testSineL16(440, 0.5, 1024)) }
+	shifter := NewDefaultSpeedShifter(source)
+	_, err := shifter.Seek(0, io.SeekStart)
+	if err != ErrNotSeekable { t.Fatalf("expected ErrNotSeekable, got %v", err) }
+	_, err = shifter.SeekSeamless(0, io.SeekStart)
+	if err != ErrNotSeekable { t.Fatalf("expected ErrNotSeekable, got %v", err) }
+
+	// the shifter must remain usable
+	_, err = io.ReadFull(shifter, make([]byte, 256))
+	if err != nil { t.Fatal(err) }
+}
+
+func TestSpeedShifterCapacity(t *testing.T) {
+	// the buffer multiplier must not affect the results
+	source := testSineL16(440, 0.5, 4096)
+	reference, err := io.ReadAll(NewSpeedShifter(bytes.NewReader(source), 0.7, 6, InterpHermite6Pt3Ord))
+	if err != nil { t.Fatal(err) }
+	for _, multiplier := range []int{1, 2, 3, 32} {
+		shifter := NewSpeedShifterWithCapacity(bytes.NewReader(source), 0.7, 6, InterpHermite6Pt3Ord, multiplier)
+		output, err := io.ReadAll(shifter)
+		if err != nil { t.Fatal(err) }
+		if !bytes.Equal(reference, output) { t.Fatalf("multiplier %d gave different results", multiplier) }
+	}
+}
+
+func TestSpeedShifterReadFloat64(t *testing.T) {
+	input := testSineL16(440, 0.5, testSampleRate)[0 : 4000*4]
+	for _, normalized := range []bool{ false, true } {
+		l16 := NewDefaultSpeedShifter(bytes.NewReader(input))
+		floats := NewDefaultSpeedShifter(bytes.NewReader(input))
+		for _, shifter := range []*SpeedShifter{ l16, floats } {
+			shifter.SetSpeed(1.3)
+			shifter.SetNormalized(normalized)
+		}
+		expected, err := io.ReadAll(l16)
+		if err != nil { t.Fatal(err) }
+
+		// read with uneven sizes to also cross buffer boundaries
+		left, right := make([]float64, 0, len(expected)/4), make([]float64, 0, len(expected)/4)
+		readLeft, readRight := make([]float64, 333), make([]float64, 400)
+		for {
+			n, err := floats.ReadFloat64(readLeft, readRight)
+			if n > 333 { t.Fatalf("ReadFloat64 returned %d samples, more than the shortest slice", n) }
+			left, right = append(left, readLeft[0 : n]...), append(right, readRight[0 : n]...)
+			if err == io.EOF { break }
+			if err != nil { t.Fatal(err) }
+		}
+
+		if len(left) != len(expected)/4 { t.Fatalf("expected %d samples, got %d", len(expected)/4, len(left)) }
+		for i := range left {
+			expectLeft, expectRight := GetSampleAsF64(expected[i*4 : ])
+			if math.Abs(left[i] - expectLeft) > 1.0/32767 || math.Abs(right[i] - expectRight) > 1.0/32767 {
+				t.Fatalf("normalized %t, sample %d: expected (%f, %f), got (%f, %f)", normalized, i, expectLeft, expectRight, left[i], right[i])
+			}
+		}
+	}
+}
+
+func TestSpeedShifterRampContinuity(t *testing.T) {
+	// a linear ramp is reproduced exactly by the interpolators, so any
+	// skipped or duplicated source frame shows up as a step in the output
+	const frames = 8000
+	input := make([]byte, frames*4)
+	for i := 0; i < frames; i++ {
+		StoreL16Sample(input[i*4 : ], int16(i*4), int16(-i*4))
+	}
+
+	for speed := 0.31; speed < 3.9; speed += 0.037 {
+		for _, readSize := range []int{ 4, 12, 100, 1000, 4096 } {
+		for _, chunked := range []bool{ false, true } {
+			var source io.Reader = bytes.NewReader(input)
+			if chunked { source = &testChunkedReader{ reader: source } }
+			shifter := NewDefaultSpeedShifter(source)
+			shifter.SetSpeed(speed)
+			output := make([]byte, 0, int(frames/speed + 1)*4)
+			buffer := make([]byte, readSize)
+			for {
+				n, err := shifter.Read(buffer)
+				output = append(output, buffer[0 : n]...)
+				if err == io.EOF { break }
+				if err != nil { t.Fatal(err) }
+			}
+
+			// the ends are affected by the priming and the zero padding
+			// after EOF, so only frames away from them are checked. the
+			// steps can deviate slightly from 4*speed due to truncation
+			margin := int(8/speed) + 2
+			for i := margin; i < len(output)/4 - margin; i++ {
+				prev, _ := GetSampleAsI16(output[(i - 1)*4 : ])
+				curr, _ := GetSampleAsI16(output[i*4 : ])
+				step := float64(curr) - float64(prev)
+				if math.Abs(step - 4*speed) > 2 {
+					t.Fatalf("speed %.3f, read size %d, chunked %t: discontinuity at frame %d (step %.0f, expected %.2f)", speed, readSize, chunked, i, step, 4*speed)
+				}
+			}
+		}
+		}
+	}
+}
+
+// A reader that returns data in small chunks of irregular sizes,
+// which are often not aligned to whole samples.
+type testChunkedReader struct {
+	reader io.Reader
+	reads int
+}
+
+func (self *testChunkedReader) Read(buffer []byte) (int, error) {
+	sizes := [...]int{ 1, 3, 6, 7, 13, 2, 40, 5 }
+	size := sizes[self.reads % len(sizes)]
+	self.reads += 1
+	if size > len(buffer) { size = len(buffer) }
+	return self.reader.Read(buffer[0 : size])
+}
@@ -0,0 +1,73 @@
+package edau
+
+import "io"
+import "sync"
+
+// A StereoWidener wraps an audio stream and adjusts its stereo width
+// through mid/side processing: the mid (L + R)/2 and side (L - R)/2
+// signals are computed, the side is scaled by the width factor, and
+// then both are recombined.
+type StereoWidener struct {
+	mutex sync.Mutex
+	source io.Reader
+	width float64
+}
+
+// Creates a new [StereoWidener]. A width of 1 leaves the audio unchanged,
+// 0 collapses it to mono and values above 1 make it wider. Values above
+// 2 are rarely useful. This method will panic if the width is negative.
+func NewStereoWidener(source io.Reader, width float64) *StereoWidener {
+	assertWidthValidity(width)
+	return &StereoWidener{ source: source, width: width }
+}
+
+// Returns the underlying source.
+func (self *StereoWidener) Source() io.Reader {
+	return self.source
+}
+
+// Returns the currently configured width.
+func (self *StereoWidener) Width() float64 {
+	self.mutex.Lock()
+	width := self.width
+	self.mutex.Unlock()
+	return width
+}
+
+// Sets the width. This method will panic if the width is negative.
+func (self *StereoWidener) SetWidth(width float64) {
+	assertWidthValidity(width)
+	self.mutex.Lock()
+	self.width = width
+	self.mutex.Unlock()
+}
+
+// Implements [io.Reader]. The returned read length will always be
+// multiple of 4, aligning to Ebitengine's sample size.
+func (self *StereoWidener) Read(buffer []byte) (int, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	n, err := readFrames(self.source, buffer)
+	for i := 0; i < n; i += 4 {
+		left, right := GetSampleAsI16(buffer[i : ])
+		mid  := (float64(left) + float64(right))/2.0
+		side := self.width*(float64(left) - float64(right))/2.0
+		StoreF64SampleAsL16(buffer[i : ], mid + side, mid - side)
+	}
+	return n, err
+}
+
+// Implements [io.Seeker].
+//
+// If the underlying source doesn't implement [io.Seeker], [ErrNotSeekable]
+// is returned.
+func (self *StereoWidener) Seek(offset int64, whence int) (int64, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	return seekSource(self.source, offset, whence)
+}
+
+func assertWidthValidity(width float64) {
+	if width < 0 { panic("width can't be negative") }
+}
@@ -0,0 +1,117 @@
+package edau
+
+import "io"
+import "sync"
+import "time"
+
+// A SwitchableSource is an audio stream whose underlying source can be
+// replaced at any time with [SwitchableSource.Switch]. The main use is
+// changing the background music of a game without having to create a
+// new Ebitengine player, which would cause a gap in the playback.
+//
+// Switches take effect at the start of the next Read. By default the
+// change is immediate, but a short crossfade can be configured with
+// [SwitchableSource.SetCrossfade] to avoid clicks. During the crossfade,
+// the previous source keeps being read and mixed with the new one.
+// Previous sources that reach [io.EOF] or fail during the crossfade are
+// considered silent for the rest of it.
+//
+// Samples are assumed to be 16-bit.
+type SwitchableSource struct {
+	mutex sync.Mutex
+	current io.Reader
+	previous io.Reader // nil if not crossfading
+	previousBuffer []byte
+	sampleRate int
+	crossfadeFrames int64
+	fadeFrames int64
+	fadeProgress int64
+}
+
+// Creates a new [SwitchableSource] initially playing the given source.
+// The sample rate is only used to convert crossfade durations to frames.
+// If the sample rate is 0, [DefaultSampleRate] is used.
+func NewSwitchableSource(source io.Reader, sampleRate int) *SwitchableSource {
+	sampleRate = resolveSampleRate(sampleRate, "NewSwitchableSource")
+	return &SwitchableSource {
+		current: source,
+		sampleRate: sampleRate,
+	}
+}
+
+// Returns the source being currently played. If a crossfade is in
+// progress, this is the source being faded in.
+func (self *SwitchableSource) Current() io.Reader {
+	self.mutex.Lock()
+	current := self.current
+	self.mutex.Unlock()
+	return current
+}
+
+// Returns the currently configured crossfade duration.
+func (self *SwitchableSource) Crossfade() time.Duration {
+	self.mutex.Lock()
+	frames := self.crossfadeFrames
+	self.mutex.Unlock()
+	return time.Duration(frames)*time.Second/time.Duration(self.sampleRate)
+}
+
+// Sets the duration of the crossfade applied on future switches. Zero
+// disables crossfading. This method will panic if the duration is
+// negative.
+func (self *SwitchableSource) SetCrossfade(duration time.Duration) {
+	if duration < 0 { panic("SwitchableSource crossfade duration can't be negative") }
+	self.mutex.Lock()
+	self.crossfadeFrames = SamplesForDuration(duration, self.sampleRate)
+	self.mutex.Unlock()
+}
+
+// Replaces the source being played. The change happens on the next
+// Read, with a crossfade if configured. If a previous crossfade was
+// still in progress, the source that was being faded out is dropped
+// and a new crossfade starts from the current source.
+func (self *SwitchableSource) Switch(next io.Reader) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	if self.crossfadeFrames > 0 {
+		self.previous = self.current
+		self.fadeFrames = self.crossfadeFrames
+		self.fadeProgress = 0
+	} else {
+		self.previous = nil
+	}
+	self.current = next
+}
+
+// Implements [io.Reader]. The returned read length will always be
+// multiple of 4, aligning to Ebitengine's sample size. Errors, including
+// [io.EOF], come from the current source. Errors from the source being
+// faded out are not reported, and that source is dropped instead.
+func (self *SwitchableSource) Read(buffer []byte) (int, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	n, err := readFrames(self.current, buffer)
+	if self.previous == nil || n == 0 { return n, err }
+
+	// read the same amount of data from the previous source
+	if cap(self.previousBuffer) < n { self.previousBuffer = make([]byte, n) }
+	previousBuffer := self.previousBuffer[0 : n]
+	previousBytes, previousErr := io.ReadFull(self.previous, previousBuffer)
+	if previousErr != nil && previousErr != io.EOF && previousErr != io.ErrUnexpectedEOF {
+		self.previous = io.MultiReader() // drop the failing source, always io.EOF
+	}
+	for i := previousBytes; i < n; i++ { previousBuffer[i] = 0 }
+
+	// mix both sources until the crossfade is completed
+	for i := 0; i < n; i += 4 {
+		if self.fadeProgress >= self.fadeFrames { break }
+		t := float64(self.fadeProgress)/float64(self.fadeFrames)
+		left, right := GetSampleAsF64(buffer[i : ])
+		prevLeft, prevRight := GetSampleAsF64(previousBuffer[i : ])
+		StoreNormF64SampleAsL16(buffer[i : ], left*t + prevLeft*(1.0 - t), right*t + prevRight*(1.0 - t))
+		self.fadeProgress += 1
+	}
+	if self.fadeProgress >= self.fadeFrames { self.previous = nil }
+	return n, err
+}
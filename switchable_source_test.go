@@ -0,0 +1,29 @@
+package edau
+
+import "math"
+import "time"
+import "bytes"
+import "errors"
+import "testing"
+
+func TestSwitchableSourcePreviousError(t *testing.T) {
+	const sampleRate = 4000 // 1ms = 4 frames
+	data := make([]byte, 8*4)
+	for i := 0; i < len(data); i += 4 { StoreNormF64SampleAsL16(data[i : ], 0.5, 0.5) }
+	previous := &testFailingReader{ reader: bytes.NewReader(make([]byte, 4)), err: errors.New("previous failure") }
+	source := NewSwitchableSource(previous, sampleRate)
+	source.SetCrossfade(time.Millisecond)
+	source.Switch(bytes.NewReader(data))
+
+	// the failing source is faded out as silence, without losing any data
+	buffer := make([]byte, 4)
+	for i, expected := range []float64{ 0, 0.125, 0.25, 0.375, 0.5, 0.5 } {
+		n, err := source.Read(buffer)
+		if err != nil { t.Fatalf("frame %d: unexpected error %v", i, err) }
+		if n != 4 { t.Fatalf("frame %d: expected 4 bytes, got %d", i, n) }
+		left, right := GetSampleAsF64(buffer)
+		if math.Abs(left - expected) > 0.001 || math.Abs(right - expected) > 0.001 {
+			t.Fatalf("frame %d: expected %f, got %f, %f", i, expected, left, right)
+		}
+	}
+}
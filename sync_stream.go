@@ -0,0 +1,43 @@
+package edau
+
+import "sync"
+
+// A SyncStream wraps a [StdAudioStream] and serializes all its Read, Seek
+// and Length calls with a mutex, making it safe to use the stream from
+// multiple goroutines. For example, the audio goroutine can be reading
+// samples while the UI goroutine queries the current position.
+//
+// Notice that SyncStream only protects against data races, not logical
+// races: if one goroutine seeks while another is reading, the read may
+// happen either before or after the seek, and sequences of calls (like
+// getting the position and then seeking relative to it) are not atomic.
+type SyncStream struct {
+	mutex sync.Mutex
+	stream StdAudioStream
+}
+
+// Creates a new [SyncStream] wrapping the given stream.
+func NewSyncStream(stream StdAudioStream) *SyncStream {
+	return &SyncStream{ stream: stream }
+}
+
+// Implements [io.Reader].
+func (self *SyncStream) Read(buffer []byte) (int, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	return self.stream.Read(buffer)
+}
+
+// Implements [io.Seeker].
+func (self *SyncStream) Seek(offset int64, whence int) (int64, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	return self.stream.Seek(offset, whence)
+}
+
+// Returns the length of the underlying stream, in bytes.
+func (self *SyncStream) Length() int64 {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	return self.stream.Length()
+}
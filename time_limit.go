@@ -0,0 +1,72 @@
+package edau
+
+import "io"
+import "time"
+
+// Creates a stream that plays at most the given duration of the source,
+// and then returns [io.EOF]. This is useful for previews, like playing
+// only the first seconds of each track in a music selection menu. The
+// duration can't be negative. This method will panic otherwise. If the
+// sample rate is 0, [DefaultSampleRate] is used.
+//
+// The stream doesn't seek the source, it simply truncates it from its
+// current position, so it doesn't implement [io.Seeker]. Use a [Section]
+// instead if you need to play a seekable range. The cut at the end can
+// click if the audio is not silent at that point, see [NewTimeLimitWithFade]
+// to avoid that.
+func NewTimeLimit(source io.Reader, max time.Duration, sampleRate int) io.Reader {
+	return newTimeLimit(source, max, 0, sampleRate, "NewTimeLimit")
+}
+
+// Like [NewTimeLimit], but fading out the audio during the last part of
+// the playback, following [FadeSCurve]. A fade of 10ms is typically enough
+// to avoid clicks, while longer fades can make the end of the preview more
+// pleasant. If the fade is longer than the max duration, the whole stream
+// fades out. This method will panic if any of the durations are negative.
+// If the sample rate is 0, [DefaultSampleRate] is used.
+func NewTimeLimitWithFade(source io.Reader, max, fadeOut time.Duration, sampleRate int) io.Reader {
+	return newTimeLimit(source, max, fadeOut, sampleRate, "NewTimeLimitWithFade")
+}
+
+func newTimeLimit(source io.Reader, max, fadeOut time.Duration, sampleRate int, caller string) io.Reader {
+	sampleRate = resolveSampleRate(sampleRate, caller)
+	if max < 0 { panic(caller + " max duration can't be negative") }
+	if fadeOut < 0 { panic(caller + " fadeOut duration can't be negative") }
+	if fadeOut > max { fadeOut = max }
+	return &timeLimitStream {
+		source: source,
+		limitFrames: SamplesForDuration(max, sampleRate),
+		fadeFrames: SamplesForDuration(fadeOut, sampleRate),
+	}
+}
+
+type timeLimitStream struct {
+	source io.Reader
+	limitFrames int64
+	fadeFrames int64
+	position int64 // in frames
+}
+
+func (self *timeLimitStream) Read(buffer []byte) (int, error) {
+	remaining := self.limitFrames - self.position
+	if remaining <= 0 { return 0, io.EOF }
+	if int64(len(buffer)) > remaining*4 {
+		buffer = buffer[0 : remaining*4]
+	}
+
+	n, err := readFrames(self.source, buffer)
+	fadeStart := self.limitFrames - self.fadeFrames
+	for i := 0; i < n; i += 4 {
+		frame := self.position + int64(i/4)
+		if frame < fadeStart { continue }
+		progress := float64(self.limitFrames - frame)/float64(self.fadeFrames + 1)
+		gain := FadeSCurve.At(progress)
+		left, right := GetSampleAsF64(buffer[i : ])
+		StoreNormF64SampleAsL16(buffer[i : ], left*gain, right*gain)
+	}
+	self.position += int64(n/4)
+	if err == nil && self.position >= self.limitFrames {
+		err = io.EOF
+	}
+	return n, err
+}
@@ -0,0 +1,40 @@
+package edau
+
+import "io"
+import "bytes"
+import "math"
+import "time"
+import "testing"
+
+func TestTimeLimit(t *testing.T) {
+	const sampleRate = 1000
+	data := make([]byte, 4*sampleRate) // 1s
+	for i := 0; i < len(data); i += 4 { StoreNormF64SampleAsL16(data[i : ], 0.5, -0.5) }
+
+	// plain truncation
+	output, err := io.ReadAll(NewTimeLimit(NewPCMBuffer(data), 250*time.Millisecond, sampleRate))
+	if err != nil { t.Fatal(err) }
+	if len(output) != 250*4 { t.Fatalf("expected %d bytes, got %d", 250*4, len(output)) }
+	if !bytes.Equal(output, data[0 : 250*4]) { t.Fatal("unexpected modifications to the audio") }
+
+	// limit beyond the source length
+	output, err = io.ReadAll(NewTimeLimit(NewPCMBuffer(data), 2*time.Second, sampleRate))
+	if err != nil { t.Fatal(err) }
+	if len(output) != len(data) { t.Fatalf("expected %d bytes, got %d", len(data), len(output)) }
+
+	// fade out over the last 100ms, with a source returning odd chunks
+	source := &testChunkedReader{ reader: NewPCMBuffer(data) }
+	output, err = io.ReadAll(NewTimeLimitWithFade(source, 500*time.Millisecond, 100*time.Millisecond, sampleRate))
+	if err != nil { t.Fatal(err) }
+	if len(output) != 500*4 { t.Fatalf("expected %d bytes, got %d", 500*4, len(output)) }
+	if !bytes.Equal(output[0 : 400*4], data[0 : 400*4]) { t.Fatal("unexpected modifications before the fade") }
+	prev := 0.5
+	for i := 400*4; i < len(output); i += 4 {
+		left, right := GetSampleAsF64(output[i : ])
+		if left > prev || math.Abs(left + right) > 0.0001 {
+			t.Fatalf("expected a decreasing fade at frame %d (got %f, %f after %f)", i/4, left, right, prev)
+		}
+		prev = left
+	}
+	if prev > 0.01 { t.Fatalf("expected the fade to end near silence, got %f", prev) }
+}
@@ -0,0 +1,89 @@
+package edau
+
+import "io"
+import "sync"
+
+// A Tremolo wraps an audio stream and modulates its amplitude with a
+// low frequency oscillator (LFO), producing a pulsing volume effect.
+//
+// The LFO phase advances once per sample and is preserved across reads
+// and seeks, so parameter changes and reads never introduce clicks.
+type Tremolo struct {
+	mutex sync.Mutex
+	source io.Reader
+	depth float64
+	lfo *LFO
+}
+
+// Creates a new [Tremolo]. The rate must be in [0, sampleRate/2) and the
+// depth in [0, 1]. A depth of 0 leaves the audio unchanged, while a depth
+// of 1 makes the volume go down to silence at the bottom of each pulse.
+// This method will panic if any of the values are invalid.
+// If the sample rate is 0, [DefaultSampleRate] is used.
+func NewTremolo(source io.Reader, rateHz, depth float64, sampleRate int) *Tremolo {
+	sampleRate = resolveSampleRate(sampleRate, "NewTremolo")
+	assertUnitRangeParam("depth", depth)
+	return &Tremolo {
+		source: source,
+		lfo: NewLFO(LFOSine, rateHz, sampleRate),
+		depth: depth,
+	}
+}
+
+// Returns the underlying source.
+func (self *Tremolo) Source() io.Reader {
+	return self.source
+}
+
+// Returns the currently configured LFO rate, in Hz.
+func (self *Tremolo) Rate() float64 {
+	return self.lfo.Rate()
+}
+
+// Sets the LFO rate, in Hz. This method will panic if the value
+// is negative or above the Nyquist frequency.
+func (self *Tremolo) SetRate(rateHz float64) {
+	self.lfo.SetRate(rateHz)
+}
+
+// Returns the currently configured depth.
+func (self *Tremolo) Depth() float64 {
+	self.mutex.Lock()
+	depth := self.depth
+	self.mutex.Unlock()
+	return depth
+}
+
+// Sets the depth, which must be in [0, 1]. This method will
+// panic if the value is invalid.
+func (self *Tremolo) SetDepth(depth float64) {
+	assertUnitRangeParam("depth", depth)
+	self.mutex.Lock()
+	self.depth = depth
+	self.mutex.Unlock()
+}
+
+// Implements [io.Reader]. The returned read length will always be
+// multiple of 4, aligning to Ebitengine's sample size.
+func (self *Tremolo) Read(buffer []byte) (int, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	n, err := readFrames(self.source, buffer)
+	for i := 0; i < n; i += 4 {
+		gain := 1.0 - self.depth*(0.5 + 0.5*self.lfo.Next())
+		left, right := GetSampleAsF64(buffer[i : ])
+		StoreNormF64SampleAsL16(buffer[i : ], left*gain, right*gain)
+	}
+	return n, err
+}
+
+// Implements [io.Seeker]. The LFO phase is not reset.
+//
+// If the underlying source doesn't implement [io.Seeker], [ErrNotSeekable]
+// is returned.
+func (self *Tremolo) Seek(offset int64, whence int) (int64, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	return seekSource(self.source, offset, whence)
+}
@@ -3,6 +3,7 @@ package edau
 import "os"
 import "io"
 import "fmt"
+import "math"
 import "strings"
 import "errors"
 
@@ -22,8 +23,35 @@ type StdAudioStream interface {
 // from it with audio.CurrentContext().SampleRate().
 var ErrAudioContextUninitialized = errors.New("Ebitengine's audio context not initialized")
 
-// Loads an .ogg, .mp3 or .wav file as a [StdAudioStream]. Additionally,
-// the returned interface also implements [io.Closer], which can be used
+const opusSampleRate = 48000 // opus is always decoded at 48kHz
+
+// Returned when trying to load .opus files without an Opus decoder. Ebitengine
+// doesn't provide one, so edau relies on an external decoder that has to be
+// registered with [RegisterOpusDecoder]. The github.com/tinne26/edau/opus module
+// does this automatically when imported. Alternatively, the files can be
+// converted to .ogg (Vorbis).
+var ErrOpusUnsupported = errors.New("opus decoding requires registering a decoder (see github.com/tinne26/edau/opus)")
+
+var opusDecoder func(io.Reader) (StdAudioStream, error) // nil if not registered
+
+// Registers the decoder used for .opus files, which must return the audio
+// as a L16 little-endian stream with two channels at 48kHz (Opus' native
+// sample rate). The result will be resampled as needed. This is typically
+// called from the init function of the package providing the decoder, like
+// github.com/tinne26/edau/opus, and must not be called concurrently with the
+// functions that load audio files.
+//
+// The decoder is kept in a separate module so edau doesn't force its
+// dependencies and minimum Go version on everyone. To enable Opus:
+//    import _ "github.com/tinne26/edau/opus"
+func RegisterOpusDecoder(decoder func(reader io.Reader) (StdAudioStream, error)) {
+	opusDecoder = decoder
+}
+
+// Loads an .ogg, .mp3, .wav or .opus file as a [StdAudioStream]. Opus decoding
+// requires a decoder registered with [RegisterOpusDecoder]. Without it, .opus
+// files return [ErrOpusUnsupported].
+// Additionally, the returned interface also implements [io.Closer], which can be used
 // to close the file associated to the stream, e.g.:
 //    err := audioStream.(io.Closer).Close()
 // The sample rate used is taken from Ebitengine's audio.CurrentContext().
@@ -33,21 +61,141 @@ func LoadAudioFileAsStream(filename string) (StdAudioStream, error) {
 	ctx := audio.CurrentContext()
 	if ctx == nil { return nil, ErrAudioContextUninitialized }
 
+	format := audioFileFormat(filename)
+	if format == "" { return nil, fmt.Errorf("unexpected audio format for '%s'", filename) }
 	file, err := os.Open(filename)
 	if err != nil { return nil, err }
-	
-	var stream StdAudioStream
-	if strings.HasSuffix(filename, ".wav") {
-		stream, err = wav.DecodeWithSampleRate(ctx.SampleRate(), file)
-	} else if strings.HasSuffix(filename, ".ogg") {
-		stream, err = vorbis.DecodeWithSampleRate(ctx.SampleRate(), file)
-	} else if strings.HasSuffix(filename, ".mp3") {
-		stream, err = mp3.DecodeWithSampleRate(ctx.SampleRate(), file)
-	} else {
-		return nil, fmt.Errorf("unexpected audio format for '%s'", filename)
+
+	stream, err := decodeAudio(file, format, ctx.SampleRate())
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &streamWithClose{ stream, file }, nil
+}
+
+// Like [LoadAudioFileAsStream], but the audio is fully decoded and downmixed
+// to mono with [StereoToMono], and the file is closed before returning. Only
+// the mono data is kept in memory, which requires half the space of the
+// stereo data, and it's expanded back with [MonoToStereo] while reading, so
+// the returned stream still uses Ebitengine's 2 channel format, with both
+// channels being equal. Length() reports the length of this 2 channel data.
+//
+// The returned stream also implements [io.Closer] for consistency with
+// [LoadAudioFileAsStream], but closing it is a no-op.
+func LoadAudioFileAsMono(filename string) (StdAudioStream, error) {
+	stream, err := LoadAudioFileAsStream(filename)
+	if err != nil { return nil, err }
+	defer stream.(io.Closer).Close()
+
+	// read and downmix in chunks, so the full stereo data
+	// is never held in memory
+	data := make([]byte, 0, stream.Length()/2)
+	chunk := make([]byte, 16*1024)
+	for {
+		n, err := readFrames(stream, chunk)
+		n = StereoToMono(chunk, chunk[0 : n])
+		data = append(data, chunk[0 : n]...)
+		if err == io.EOF { break }
+		if err != nil { return nil, err }
+		if n == 0 { return nil, io.ErrNoProgress }
 	}
+	return &monoStream{ data: data }, nil
+}
 
-	return &streamWithClose{ stream, file }, err
+// Options for [LoadAudioFileResampled]. The zero value is valid and
+// equivalent to the defaults used by [NewDefaultSpeedShifter].
+type ResampleOptions struct {
+	// The interpolator used for resampling. Defaults to [InterpHermite6Pt3Ord].
+	Interpolator InterpolatorFunc
+
+	// The interpolator's window size, which must be multiple of 2.
+	// Defaults to 6. Ignored if Interpolator is nil.
+	WindowSize int
+}
+
+// Like [LoadAudioFileAsStream], but if the file's sample rate doesn't match
+// the audio context's sample rate, the resampling is done with edau's own
+// interpolators instead of Ebitengine's, which allows controlling the quality
+// and CPU cost of the process through the given options. For example, offline
+// asset processing may use a more expensive interpolator with a bigger window,
+// while the defaults are a good fit for runtime.
+//
+// The resampling happens in real-time while reading. Seeking is supported,
+// but as explained in [SpeedShifter.Seek], it resets the interpolation window.
+func LoadAudioFileResampled(filename string, options ResampleOptions) (StdAudioStream, error) {
+	ctx := audio.CurrentContext()
+	if ctx == nil { return nil, ErrAudioContextUninitialized }
+	if options.Interpolator == nil {
+		options.Interpolator, options.WindowSize = InterpHermite6Pt3Ord, 6
+	}
+	if options.WindowSize < 2 || options.WindowSize % 2 != 0 {
+		return nil, errors.New("ResampleOptions.WindowSize must be an even number >= 2")
+	}
+
+	format := audioFileFormat(filename)
+	if format == "" { return nil, fmt.Errorf("unexpected audio format for '%s'", filename) }
+	file, err := os.Open(filename)
+	if err != nil { return nil, err }
+
+	// probe the file's sample rate and decode without resampling
+	sampleRate, _, err := probeAudioHeader(file, format)
+	if err == nil { _, err = file.Seek(0, io.SeekStart) }
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	stream, err := decodeAudio(file, format, sampleRate)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	if sampleRate != ctx.SampleRate() {
+		stream = newResampledStream(stream, sampleRate, ctx.SampleRate(), options)
+	}
+	return &streamWithClose{ stream, file }, nil
+}
+
+// Returns "wav", "ogg", "mp3" or "opus" based on the filename's
+// extension, or an empty string if the format is unknown.
+func audioFileFormat(filename string) string {
+	for _, format := range []string{ "wav", "ogg", "mp3", "opus" } {
+		if strings.HasSuffix(filename, "." + format) { return format }
+	}
+	return ""
+}
+
+func decodeAudio(reader io.Reader, format string, sampleRate int) (StdAudioStream, error) {
+	switch format {
+	case "wav": return wav.DecodeWithSampleRate(sampleRate, reader)
+	case "ogg": return vorbis.DecodeWithSampleRate(sampleRate, reader)
+	case "mp3": return mp3.DecodeWithSampleRate(sampleRate, reader)
+	case "opus":
+		if opusDecoder == nil { return nil, ErrOpusUnsupported }
+		stream, err := opusDecoder(reader)
+		if err != nil || sampleRate == opusSampleRate { return stream, err }
+		options := ResampleOptions{ Interpolator: InterpHermite6Pt3Ord, WindowSize: 6 }
+		return newResampledStream(stream, opusSampleRate, sampleRate, options), nil
+	default:
+		panic("unexpected audio format '" + format + "'")
+	}
+}
+
+// Returns the current playback position of the given stream, in seconds.
+// The position is obtained with Seek(0, io.SeekCurrent), so any error from
+// the stream's Seek method will be returned as is.
+func StreamPositionSeconds(stream StdAudioStream, sampleRate int) (float64, error) {
+	if sampleRate <= 0 { panic("StreamPositionSeconds sampleRate must be strictly positive") }
+	position, err := stream.Seek(0, io.SeekCurrent)
+	if err != nil { return 0, err }
+	return float64(position/4)/float64(sampleRate), nil
+}
+
+// Returns the total duration of the given stream, in seconds, based
+// on its Length().
+func StreamDurationSeconds(stream StdAudioStream, sampleRate int) float64 {
+	if sampleRate <= 0 { panic("StreamDurationSeconds sampleRate must be strictly positive") }
+	return float64(stream.Length()/4)/float64(sampleRate)
 }
 
 type streamWithClose struct {
@@ -68,3 +216,91 @@ func (self *streamWithClose) Close() error {
 	return self.file.Close()
 }
 
+// A stream resampled in real-time with a [SpeedShifter].
+type resampledStream struct {
+	shifter *SpeedShifter
+	ratio float64 // source sample rate / target sample rate
+	position int64
+	length int64
+}
+
+func newResampledStream(source StdAudioStream, fromRate, toRate int, options ResampleOptions) *resampledStream {
+	ratio := float64(fromRate)/float64(toRate)
+	shifter := NewSpeedShifter(source, ratio, options.WindowSize, options.Interpolator)
+	shifter.SetSpeedLimits(ratio, ratio)
+	shifter.SetSpeed(ratio)
+	sourceFrames := source.Length()/4
+	return &resampledStream {
+		shifter: shifter,
+		ratio: ratio,
+		length: int64(math.Ceil(float64(sourceFrames)/ratio))*4,
+	}
+}
+
+func (self *resampledStream) Read(buffer []byte) (int, error) {
+	if int64(len(buffer)) > self.length - self.position {
+		buffer = buffer[0 : self.length - self.position]
+		if len(buffer) == 0 { return 0, io.EOF }
+	}
+	n, err := self.shifter.Read(buffer)
+	self.position += int64(n)
+	return n, err
+}
+
+func (self *resampledStream) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart  : // nothing to adjust
+	case io.SeekCurrent: offset += self.position
+	case io.SeekEnd    : offset += self.length
+	default:
+		return self.position, errors.New("invalid whence")
+	}
+	if offset < 0 { return self.position, errors.New("negative position") }
+	offset -= offset & 0b11
+
+	sourceFrame := int64(float64(offset/4)*self.ratio)
+	_, err := self.shifter.Seek(sourceFrame*4, io.SeekStart)
+	if err != nil { return self.position, err }
+	self.position = offset
+	return offset, nil
+}
+
+func (self *resampledStream) Length() int64 {
+	return self.length
+}
+
+
+// A stream of mono data served as 2 channel data.
+type monoStream struct {
+	data []byte // L16, 1 channel
+	position int64 // in 2 channel bytes
+}
+
+func (self *monoStream) Read(buffer []byte) (int, error) {
+	monoPos := self.position/2
+	if monoPos >= int64(len(self.data)) { return 0, io.EOF }
+	n := MonoToStereo(buffer, self.data[monoPos : ])
+	self.position += int64(n)
+	return n, nil
+}
+
+func (self *monoStream) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart  : // nothing to adjust
+	case io.SeekCurrent: offset += self.position
+	case io.SeekEnd    : offset += self.Length()
+	default:
+		return self.position, errors.New("invalid whence")
+	}
+	if offset < 0 { return self.position, errors.New("negative position") }
+	self.position = offset - (offset & 0b11)
+	return self.position, nil
+}
+
+func (self *monoStream) Length() int64 {
+	return int64(len(self.data))*2
+}
+
+func (self *monoStream) Close() error {
+	return nil
+}
@@ -0,0 +1,22 @@
+package edau
+
+import "io"
+import "bytes"
+import "testing"
+
+func TestOpusDecoderRegistration(t *testing.T) {
+	_, err := decodeAudio(bytes.NewReader(nil), "opus", 48000)
+	if err != ErrOpusUnsupported { t.Fatalf("expected ErrOpusUnsupported, got %v", err) }
+
+	// registered decoders return 48kHz audio, resampled as needed
+	RegisterOpusDecoder(func(reader io.Reader) (StdAudioStream, error) {
+		return NewPCMBuffer(make([]byte, 279*4)), nil
+	})
+	defer RegisterOpusDecoder(nil)
+	stream, err := decodeAudio(bytes.NewReader(nil), "opus", 48000)
+	if err != nil { t.Fatal(err) }
+	if stream.Length() != 279*4 { t.Fatalf("expected %d bytes, got %d", 279*4, stream.Length()) }
+	stream, err = decodeAudio(bytes.NewReader(nil), "opus", 24000)
+	if err != nil { t.Fatal(err) }
+	if stream.Length() != 140*4 { t.Fatalf("expected %d bytes, got %d", 140*4, stream.Length()) }
+}
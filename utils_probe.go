@@ -0,0 +1,152 @@
+package edau
+
+import "os"
+import "io"
+import "fmt"
+import "errors"
+import "encoding/binary"
+
+var errUnrecognizedHeader = errors.New("unrecognized audio file header")
+
+// Reads the native sample rate and number of channels of an audio file
+// from its headers, without decoding any audio data. The format is returned
+// as the file extension without the dot: "wav", "ogg", "mp3" or "opus".
+// This can be used to decide whether resampling is needed before choosing
+// a decoding path, e.g. [LoadAudioFileAsStream] or [LoadAudioFileResampled].
+//
+// Notice that for mp3 files, the values are taken from the first frame.
+func ProbeAudioFile(filename string) (int, int, string, error) {
+	format := audioFileFormat(filename)
+	if format == "" { return 0, 0, "", fmt.Errorf("unexpected audio format for '%s'", filename) }
+	file, err := os.Open(filename)
+	if err != nil { return 0, 0, format, err }
+	defer file.Close()
+
+	sampleRate, channels, err := probeAudioHeader(file, format)
+	if err == io.EOF || err == io.ErrUnexpectedEOF { err = errUnrecognizedHeader }
+	return sampleRate, channels, format, err
+}
+
+// Reads the sample rate and number of channels from the headers of a
+// wav, ogg, mp3 or opus file, without decoding any audio data. The format is
+// given as the file extension, without the dot.
+func probeAudioHeader(reader io.Reader, format string) (int, int, error) {
+	switch format {
+	case "wav": return probeWavHeader(reader)
+	case "ogg": return probeOggHeader(reader)
+	case "mp3": return probeMp3Header(reader)
+	case "opus": return probeOpusHeader(reader)
+	default:
+		return 0, 0, errors.New("unexpected audio format '" + format + "'")
+	}
+}
+
+func probeWavHeader(reader io.Reader) (int, int, error) {
+	var header [12]byte
+	_, err := io.ReadFull(reader, header[:])
+	if err != nil { return 0, 0, err }
+	if string(header[0 : 4]) != "RIFF" || string(header[8 : 12]) != "WAVE" {
+		return 0, 0, errUnrecognizedHeader
+	}
+
+	// find the "fmt " chunk
+	for {
+		var chunkHeader [8]byte
+		_, err := io.ReadFull(reader, chunkHeader[:])
+		if err != nil { return 0, 0, err }
+		size := int64(binary.LittleEndian.Uint32(chunkHeader[4 : ]))
+		if string(chunkHeader[0 : 4]) == "fmt " {
+			var format [8]byte
+			_, err := io.ReadFull(reader, format[:])
+			if err != nil { return 0, 0, err }
+			channels := int(binary.LittleEndian.Uint16(format[2 : ]))
+			sampleRate := int(binary.LittleEndian.Uint32(format[4 : ]))
+			return sampleRate, channels, nil
+		}
+		_, err = io.CopyN(io.Discard, reader, size + (size & 1)) // chunks are padded to even sizes
+		if err != nil { return 0, 0, err }
+	}
+}
+
+func probeOggHeader(reader io.Reader) (int, int, error) {
+	// the first page contains only the vorbis identification header
+	var pageHeader [27]byte
+	_, err := io.ReadFull(reader, pageHeader[:])
+	if err != nil { return 0, 0, err }
+	if string(pageHeader[0 : 4]) != "OggS" { return 0, 0, errUnrecognizedHeader }
+	_, err = io.CopyN(io.Discard, reader, int64(pageHeader[26])) // segment table
+	if err != nil { return 0, 0, err }
+
+	var idHeader [16]byte
+	_, err = io.ReadFull(reader, idHeader[:])
+	if err != nil { return 0, 0, err }
+	if idHeader[0] != 1 || string(idHeader[1 : 7]) != "vorbis" { return 0, 0, errUnrecognizedHeader }
+	channels := int(idHeader[11])
+	sampleRate := int(binary.LittleEndian.Uint32(idHeader[12 : ]))
+	return sampleRate, channels, nil
+}
+
+// Opus streams are always decoded at 48kHz, regardless of the
+// input sample rate stored in the header.
+func probeOpusHeader(reader io.Reader) (int, int, error) {
+	var pageHeader [27]byte
+	_, err := io.ReadFull(reader, pageHeader[:])
+	if err != nil { return 0, 0, err }
+	if string(pageHeader[0 : 4]) != "OggS" { return 0, 0, errUnrecognizedHeader }
+	_, err = io.CopyN(io.Discard, reader, int64(pageHeader[26])) // segment table
+	if err != nil { return 0, 0, err }
+
+	var idHeader [10]byte
+	_, err = io.ReadFull(reader, idHeader[:])
+	if err != nil { return 0, 0, err }
+	if string(idHeader[0 : 8]) != "OpusHead" { return 0, 0, errUnrecognizedHeader }
+	return 48000, int(idHeader[9]), nil
+}
+
+var mp3SampleRates = [3][3]int{
+	{ 44100, 48000, 32000 }, // MPEG 1
+	{ 22050, 24000, 16000 }, // MPEG 2
+	{ 11025, 12000,  8000 }, // MPEG 2.5
+}
+
+func probeMp3Header(reader io.Reader) (int, int, error) {
+	var header [10]byte
+	_, err := io.ReadFull(reader, header[0 : 4])
+	if err != nil { return 0, 0, err }
+
+	// skip ID3v2 tag if present
+	if string(header[0 : 3]) == "ID3" {
+		_, err = io.ReadFull(reader, header[4 : 10])
+		if err != nil { return 0, 0, err }
+		size := int64(header[6])<<21 | int64(header[7])<<14 | int64(header[8])<<7 | int64(header[9])
+		if header[5] & 0x10 != 0 { size += 10 } // footer
+		_, err = io.CopyN(io.Discard, reader, size)
+		if err != nil { return 0, 0, err }
+		_, err = io.ReadFull(reader, header[0 : 4])
+		if err != nil { return 0, 0, err }
+	}
+
+	// find the first frame sync (a few padding bytes may precede it)
+	const maxScan = 64*1024
+	for scanned := 0; ; scanned++ {
+		if header[0] == 0xFF && header[1] & 0xE0 == 0xE0 {
+			version := (header[1] >> 3) & 0b11
+			rateIndex := (header[2] >> 2) & 0b11
+			if version != 0b01 && rateIndex != 0b11 {
+				var versionIndex int
+				switch version {
+				case 0b11: versionIndex = 0
+				case 0b10: versionIndex = 1
+				case 0b00: versionIndex = 2
+				}
+				channels := 2
+				if header[3] >> 6 == 0b11 { channels = 1 }
+				return mp3SampleRates[versionIndex][rateIndex], channels, nil
+			}
+		}
+		if scanned >= maxScan { return 0, 0, errUnrecognizedHeader }
+		copy(header[0 : 3], header[1 : 4])
+		_, err = io.ReadFull(reader, header[3 : 4])
+		if err != nil { return 0, 0, err }
+	}
+}
@@ -1,11 +1,26 @@
 package edau
 
+import "math"
+import "time"
+import "math/rand"
+
 // Reads the first 4 bytes from the given slice and converts them from L16,
 // 2 channel, little-endian format to 2 channel float64 values in the [-1, 1]
 // range. Will panic if len(buffer) < 4.
 func GetSampleAsF64(buffer []byte) (float64, float64) {
 	left, right := GetSampleAsI16(buffer)
-	return NormalizeF64(float64(left)), NormalizeF64(float64(right))
+	return normalizeI16(left), normalizeI16(right)
+}
+
+// Like [GetSampleAsF64], but stores the left and right channel values
+// into out[0] and out[1] instead of returning them. This is convenient
+// in hot loops that process big buffers. Will panic if len(buffer) < 4.
+func ReadNormalizedFrameInto(buffer []byte, out *[2]float64) {
+	// decoded manually instead of through GetSampleAsI16 to keep the
+	// function cheap enough to be inlined
+	_ = buffer[3] // single bounds check
+	out[0] = normalizeI16(int16(buffer[1]) << 8 | int16(buffer[0]))
+	out[1] = normalizeI16(int16(buffer[3]) << 8 | int16(buffer[2]))
 }
 
 // Reads the first 4 bytes from the given slice (the first sample) and returns
@@ -24,6 +39,63 @@ func StoreF64SampleAsL16(buffer []byte, left, right float64) {
 	StoreL16Sample(buffer, clipFloatToI16(left), clipFloatToI16(right))
 }
 
+// Clipping behaviors for [StoreF64SampleAsL16Sat].
+type ClipMode uint8
+const (
+	ClipHard ClipMode = iota // out of range values are clipped to the closest limit
+	ClipSoft // values are saturated smoothly with tanh, affecting the whole range
+	ClipWrap // out of range values wrap around, like integer overflows (NaN and ±Inf become 0)
+)
+
+// Like [StoreF64SampleAsL16], but with a configurable clipping behavior.
+// Returns whether any of the values was out of the [-32768, 32767] range,
+// which can be used to detect clipping regardless of the mode. Will panic
+// if len(buffer) < 4 or the mode is invalid.
+func StoreF64SampleAsL16Sat(buffer []byte, left, right float64, mode ClipMode) bool {
+	clipped := (left < -32768 || left > 32767 || right < -32768 || right > 32767)
+	switch mode {
+	case ClipHard:
+		StoreL16Sample(buffer, clipFloatToI16(left), clipFloatToI16(right))
+	case ClipSoft:
+		left  = 32767.0*math.Tanh(left/32767.0)
+		right = 32767.0*math.Tanh(right/32767.0)
+		StoreL16Sample(buffer, clipFloatToI16(left), clipFloatToI16(right))
+	case ClipWrap:
+		StoreL16Sample(buffer, wrapFloatToI16(left), wrapFloatToI16(right))
+	default:
+		panic("invalid ClipMode")
+	}
+	return clipped
+}
+
+// A function that returns dither noise in LSB units (the distance between
+// two consecutive 16-bit values). Dither noise is added to the samples right
+// before quantizing them, which decorrelates the quantization error from the
+// signal and turns it into a constant, less noticeable noise floor. Called
+// once per channel value. See [TPDFDither] and [NoDither].
+type DitherFunc func() float64
+
+// A [DitherFunc] with triangular probability density in (-1, 1) LSB, the
+// standard choice to dither to 16 bits. Safe for concurrent use.
+func TPDFDither() float64 {
+	return rand.Float64() - rand.Float64()
+}
+
+// A [DitherFunc] that always returns zero, which disables dithering.
+func NoDither() float64 {
+	return 0
+}
+
+// Like [StoreF64SampleAsL16], but adds the given dither noise to the values
+// before quantizing them. Values are rounded to the nearest integer after the
+// noise is added, and then clipped to the [-32768, 32767] range. Will panic
+// if len(buffer) < 4 or dither is nil.
+func StoreF64SampleAsL16Dithered(buffer []byte, left, right float64, dither DitherFunc) {
+	left  = math.Round(left  + dither())
+	right = math.Round(right + dither())
+	StoreL16Sample(buffer, clipFloatToI16(left), clipFloatToI16(right))
+}
+
 // Stores the given normalized ([-1, 1]) values as a L16, 2 channel, little-endian
 // sample right at the start of the given slice. Values out of range will be clipped.
 // Will panic if len(buffer) < 4.
@@ -46,6 +118,14 @@ func clipFloatToI16(value float64) int16 {
 	return int16(value)
 }
 
+// Truncates the value and wraps it to the int16 range like an integer
+// overflow would. The modulo is taken in float64, as converting huge
+// values to integers directly is implementation-defined.
+func wrapFloatToI16(value float64) int16 {
+	if math.IsNaN(value) || math.IsInf(value, 0) { return 0 }
+	return int16(int32(math.Mod(math.Trunc(value), 65536))) // exact, in (-65536, 65536)
+}
+
 func normFloatToI16(value float64) int16 {
 	if value >= 0 {
 		if value >=  1.0 { return  32767 }
@@ -57,13 +137,240 @@ func normFloatToI16(value float64) int16 {
 }
 
 // Normalize a float64 value from [-32768, 32767] to [-1, 1].
+// Values out of range are clipped.
 func NormalizeF64(value float64) float64 {
-	// TODO: this is quite wasteful, isn't it?
-	if value >= 0 {
-		if value >=  32767 { return  1.0 }
-		return value/32767.0
-	} else { // value < 0
-		if value <= -32768 { return -1.0 }
-		return value/32768.0
+	// the sign bit selects the 32767 or 32768 divisor without branching
+	normalized := value/(32767.0 + float64(math.Float64bits(value) >> 63))
+	if normalized >  1.0 { return  1.0 }
+	if normalized < -1.0 { return -1.0 }
+	return normalized
+}
+
+// Like [NormalizeF64], but int16 values can't be out of range,
+// so no clipping is required.
+func normalizeI16(value int16) float64 {
+	// value >> 15 is -1 for negative values and 0 otherwise, so
+	// the divisor is 32768 or 32767 without branching
+	return float64(value)/(32767.0 - float64(value >> 15))
+}
+
+// A stereo sample with normalized left and right channel values,
+// typically in the [-1, 1] range.
+type StereoFrame struct {
+	L float64
+	R float64
+}
+
+// Reads the first 4 bytes from the given slice as a L16, 2 channel,
+// little-endian sample and returns it as a normalized [StereoFrame].
+// Will panic if len(buffer) < 4.
+func ReadFrame(buffer []byte) StereoFrame {
+	left, right := GetSampleAsF64(buffer)
+	return StereoFrame{ L: left, R: right }
+}
+
+// Stores the given normalized frame as a L16, 2 channel, little-endian
+// sample right at the start of the given slice. Values out of range will
+// be clipped. Will panic if len(buffer) < 4.
+func WriteFrame(buffer []byte, frame StereoFrame) {
+	StoreNormF64SampleAsL16(buffer, frame.L, frame.R)
+}
+
+// Calls the given function for each frame in the buffer, in order, and
+// stores the returned frames back into the buffer. The function receives
+// the frame index (not the byte index) and the normalized frame. Trailing
+// bytes that don't form a whole sample are left untouched.
+//
+// Example to swap the left and right channels:
+//   edau.ForEachFrame(buffer, func(_ int, frame edau.StereoFrame) edau.StereoFrame {
+//       return edau.StereoFrame{ L: frame.R, R: frame.L }
+//   })
+func ForEachFrame(buffer []byte, fn func(i int, frame StereoFrame) StereoFrame) {
+	for i := 0; (i + 1)*4 <= len(buffer); i++ {
+		WriteFrame(buffer[i*4 : ], fn(i, ReadFrame(buffer[i*4 : ])))
+	}
+}
+
+// Returns the peak absolute values of the left and right channels for the
+// given L16, 2 channel, little-endian buffer, normalized to [0, 1]. Trailing
+// bytes that don't form a whole sample are ignored.
+func MeasurePeak(buffer []byte) (float64, float64) {
+	var leftPeak, rightPeak float64
+	for len(buffer) >= 4 {
+		left, right := GetSampleAsF64(buffer)
+		leftPeak  = math.Max(leftPeak,  math.Abs(left))
+		rightPeak = math.Max(rightPeak, math.Abs(right))
+		buffer = buffer[4 : ]
 	}
-}
\ No newline at end of file
+	return leftPeak, rightPeak
+}
+
+// Like [MeasurePeak], but also considers the peaks between samples, which
+// can exceed the sample peaks and clip on digital to analog conversion even
+// if no sample is clipped. The signal is reconstructed at oversample points
+// per sample with the given interpolator, and the maximum absolute values
+// for the left and right channels are returned. Results may exceed 1.
+//
+// The interpolator receives windows of 6 samples and positions between the
+// two central samples, like in [NewDefaultSpeedShifter], so it should be
+// a 6-point interpolator like [InterpHermite6Pt3Ord] or [InterpLagrangeN].
+// Windows at the edges of the buffer repeat the first and last samples.
+//
+// The oversample factor must be at least 1 (which is equivalent to
+// MeasurePeak), with 4 being a common choice. This method will panic
+// if oversample < 1 or interp is nil.
+func MeasureTruePeak(buffer []byte, oversample int, interp InterpolatorFunc) (float64, float64) {
+	if oversample < 1 { panic("MeasureTruePeak oversample must be at least 1") }
+	if interp == nil { panic("MeasureTruePeak interp can't be nil") }
+
+	const window = 6
+	const center = window/2 - 1
+	numFrames := len(buffer)/4
+	var leftWin, rightWin [window]float64
+	var leftPeak, rightPeak float64
+	for i := 0; i < numFrames; i++ {
+		// fill the windows around the current frame
+		for k := 0; k < window; k++ {
+			index := i + k - center
+			if index < 0 { index = 0 }
+			if index >= numFrames { index = numFrames - 1 }
+			leftWin[k], rightWin[k] = GetSampleAsF64(buffer[index*4 : ])
+		}
+
+		// sample peak and inter-sample peaks up to the next frame
+		leftPeak  = math.Max(leftPeak,  math.Abs(leftWin[center]))
+		rightPeak = math.Max(rightPeak, math.Abs(rightWin[center]))
+		if i == numFrames - 1 { break }
+		for j := 1; j < oversample; j++ {
+			position := float64(center) + float64(j)/float64(oversample)
+			leftPeak  = math.Max(leftPeak,  math.Abs(interp(leftWin[:], position)))
+			rightPeak = math.Max(rightPeak, math.Abs(interp(rightWin[:], position)))
+		}
+	}
+	return leftPeak, rightPeak
+}
+
+// Returns the root mean square (RMS) of the left and right channels for the
+// given L16, 2 channel, little-endian buffer, normalized to [0, 1]. Trailing
+// bytes that don't form a whole sample are ignored. If the buffer has no
+// samples, zeros are returned.
+func MeasureRMS(buffer []byte) (float64, float64) {
+	numSamples := len(buffer)/4
+	if numSamples == 0 { return 0, 0 }
+
+	var leftSum, rightSum float64
+	for len(buffer) >= 4 {
+		left, right := GetSampleAsF64(buffer)
+		leftSum  += left*left
+		rightSum += right*right
+		buffer = buffer[4 : ]
+	}
+	return math.Sqrt(leftSum/float64(numSamples)), math.Sqrt(rightSum/float64(numSamples))
+}
+
+// Returns the number of samples that the given duration spans at the
+// given sample rate, rounding down.
+func SamplesForDuration(duration time.Duration, sampleRate int) int64 {
+	return (int64(duration)*int64(sampleRate))/int64(time.Second)
+}
+
+// Returns the duration of the given number of samples at the given
+// sample rate, rounding down to the nanosecond.
+func DurationForSamples(samples int64, sampleRate int) time.Duration {
+	seconds := samples/int64(sampleRate)
+	remainder := samples % int64(sampleRate)
+	return time.Duration(seconds)*time.Second + (time.Duration(remainder)*time.Second)/time.Duration(sampleRate)
+}
+
+// Returns the number of bytes that the given duration spans at the given
+// sample rate, assuming L16 2 channel audio (Ebitengine's default format).
+// The result is rounded down to a multiple of 4, so it's always aligned to
+// whole samples.
+func BytesForDuration(duration time.Duration, sampleRate int) int64 {
+	return SamplesForDuration(duration, sampleRate)*4
+}
+
+// Returns the duration of the given number of bytes at the given sample
+// rate, assuming L16 2 channel audio (Ebitengine's default format). Bytes
+// that don't form a whole sample are ignored.
+func DurationForBytes(bytes int64, sampleRate int) time.Duration {
+	return DurationForSamples(bytes/4, sampleRate)
+}
+
+// Multiplies all the samples in the given L16, 2 channel, little-endian
+// buffer by the given gain, in place. Values out of range will be clipped.
+// Trailing bytes that don't form a whole sample are left untouched.
+func ApplyGain(buffer []byte, gain float64) {
+	for len(buffer) >= 4 {
+		left, right := GetSampleAsF64(buffer)
+		StoreNormF64SampleAsL16(buffer, left*gain, right*gain)
+		buffer = buffer[4 : ]
+	}
+}
+
+// Returns the left and right gains for the given pan position, which must
+// be in [-1, 1] (-1 is fully left, 0 is the center and 1 is fully right),
+// following an equal-power pan law. With equal-power panning, the total
+// power stays constant across positions, so sounds don't seem to get
+// louder or quieter as they move. This means that at the center both
+// gains are ~0.707 (-3dB) instead of 1. This function will panic if the
+// pan is out of range.
+func EqualPowerPan(pan float64) (float64, float64) {
+	if pan < -1.0 || pan > 1.0 { panic("pan must be in [-1, 1]") }
+	angle := (pan + 1.0)*math.Pi/4.0
+	return math.Cos(angle), math.Sin(angle)
+}
+
+// Scales the given L16, 2 channel, little-endian buffer in place so its
+// peak (measured with [MeasurePeak] across both channels) matches the
+// target peak, which must be in (0, 1]. Returns the gain applied. If the
+// buffer is silent, it's left untouched and the returned gain is 1.
+func NormalizeBuffer(buffer []byte, targetPeak float64) float64 {
+	if targetPeak <= 0 || targetPeak > 1.0 { panic("targetPeak must be in (0, 1]") }
+	peak := math.Max(MeasurePeak(buffer))
+	if peak == 0 { return 1.0 }
+	gain := targetPeak/peak
+	ApplyGain(buffer, gain)
+	return gain
+}
+
+// Converts the L16, 2 channel, little-endian samples in src to L16, 1 channel,
+// little-endian samples in dst, averaging both channels. Returns the number of
+// bytes written to dst, which will be len(src)/2 (ignoring incomplete samples),
+// or less if dst is too small. The conversion can be done in place, passing
+// the same slice as dst and src.
+func StereoToMono(dst, src []byte) int {
+	frames := len(src)/4
+	if frames > len(dst)/2 { frames = len(dst)/2 }
+	for i := 0; i < frames; i++ {
+		left, right := GetSampleAsI16(src[i*4 : ])
+		mono := uint16((int32(left) + int32(right))/2)
+		dst[i*2 + 0] = byte(mono)
+		dst[i*2 + 1] = byte(mono >> 8)
+	}
+	return frames*2
+}
+
+// Converts the L16, 1 channel, little-endian samples in src to L16, 2 channel,
+// little-endian samples in dst, duplicating each sample on both channels.
+// Returns the number of bytes written to dst, which will be len(src)*2
+// (ignoring incomplete samples), or less if dst is too small. The conversion
+// can be done in place if src is placed at the start of dst.
+func MonoToStereo(dst, src []byte) int {
+	frames := len(src)/2
+	if frames > len(dst)/4 { frames = len(dst)/4 }
+	for i := frames - 1; i >= 0; i-- { // backwards to allow in place conversions
+		lo, hi := src[i*2 + 0], src[i*2 + 1]
+		dst[i*4 + 0], dst[i*4 + 1] = lo, hi
+		dst[i*4 + 2], dst[i*4 + 3] = lo, hi
+	}
+	return frames*4
+}
+
+// Returns the coefficient for a one-pole smoother that covers ~63% of the
+// distance to its target in the given time, as used by envelope followers.
+// Zero seconds result in a coefficient of 1, which disables the smoothing.
+func smoothingCoef(seconds float64, sampleRate int) float64 {
+	if seconds == 0 { return 1.0 }
+	return 1.0 - math.Exp(-1.0/(seconds*float64(sampleRate)))
+}
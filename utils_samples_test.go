@@ -0,0 +1,135 @@
+package edau
+
+import "math"
+import "testing"
+
+// The original NormalizeF64 implementation, kept for comparison.
+func testNormalizeF64Reference(value float64) float64 {
+	if value >= 0 {
+		if value >=  32767 { return  1.0 }
+		return value/32767.0
+	} else { // value < 0
+		if value <= -32768 { return -1.0 }
+		return value/32768.0
+	}
+}
+
+func TestNormalizeF64(t *testing.T) {
+	for _, value := range []float64{ -40000, -32768, -32767.5, -1, -0.25, 0, 0.25, 1, 32766.5, 32767, 40000 } {
+		expected, got := testNormalizeF64Reference(value), NormalizeF64(value)
+		if expected != got { t.Fatalf("NormalizeF64(%f) expected %f, got %f", value, expected, got) }
+	}
+
+	buffer := make([]byte, 4)
+	var frame [2]float64
+	for value := -32768; value <= 32767; value++ {
+		StoreL16Sample(buffer, int16(value), int16(-1 - value))
+		expectedL := testNormalizeF64Reference(float64(value))
+		expectedR := testNormalizeF64Reference(float64(-1 - value))
+		ReadNormalizedFrameInto(buffer, &frame)
+		left, right := GetSampleAsF64(buffer)
+		if frame[0] != expectedL || frame[1] != expectedR || left != expectedL || right != expectedR {
+			t.Fatalf("value %d: expected (%f, %f), got (%f, %f) and (%f, %f)", value, expectedL, expectedR, frame[0], frame[1], left, right)
+		}
+	}
+}
+
+func testNormalizationBuffer() []byte {
+	buffer := make([]byte, 4096*4)
+	for i := 0; i < len(buffer); i += 4 {
+		StoreL16Sample(buffer[i : ], int16(i*7), int16(-i*13))
+	}
+	return buffer
+}
+
+func BenchmarkNormalizeFramesReference(b *testing.B) {
+	buffer := testNormalizationBuffer()
+	var sum float64
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for i := 0; i < len(buffer); i += 4 {
+			left, right := GetSampleAsI16(buffer[i : ])
+			sum += testNormalizeF64Reference(float64(left)) + testNormalizeF64Reference(float64(right))
+		}
+	}
+	if sum == 1 { b.Log(sum) } // prevent the loop from being optimized away
+}
+
+func BenchmarkNormalizeFramesInto(b *testing.B) {
+	buffer := testNormalizationBuffer()
+	var sum float64
+	var frame [2]float64
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for i := 0; i < len(buffer); i += 4 {
+			ReadNormalizedFrameInto(buffer[i : ], &frame)
+			sum += frame[0] + frame[1]
+		}
+	}
+	if sum == 1 { b.Log(sum) } // prevent the loop from being optimized away
+}
+
+func TestMeasureTruePeak(t *testing.T) {
+	// a sine at a quarter of the sample rate with a 45 degree phase
+	// offset has all its samples at 0.707 of the real peak
+	const amplitude = 0.5
+	buffer := make([]byte, 256*4)
+	for i := 0; i < 256; i++ {
+		value := amplitude*math.Sin(math.Pi*float64(i)/2 + math.Pi/4)
+		StoreNormF64SampleAsL16(buffer[i*4 : ], value, value/2)
+	}
+
+	samplePeak, _ := MeasurePeak(buffer)
+	if math.Abs(samplePeak - amplitude/math.Sqrt2) > 0.001 {
+		t.Fatalf("expected sample peak %f, got %f", amplitude/math.Sqrt2, samplePeak)
+	}
+	left, right := MeasureTruePeak(buffer, 1, InterpHermite6Pt3Ord)
+	if left != samplePeak { t.Fatalf("expected oversample 1 to match the sample peak (%f), got %f", samplePeak, left) }
+
+	for _, interp := range []InterpolatorFunc{ InterpHermite6Pt3Ord, InterpLagrange6Pt5Ord } {
+		left, right = MeasureTruePeak(buffer, 4, interp)
+		if math.Abs(left - amplitude) > 0.05 || math.Abs(right - amplitude/2) > 0.025 {
+			t.Fatalf("expected true peaks (%f, %f), got (%f, %f)", amplitude, amplitude/2, left, right)
+		}
+	}
+}
+
+func TestStoreF64SampleAsL16Dithered(t *testing.T) {
+	// a constant value below 1 LSB vanishes without dither, but with
+	// TPDF dither the average of the quantized values must preserve it
+	const value, numSamples = 0.3, 20000
+	buffer := make([]byte, 4)
+	var sumNone, sumTPDF float64
+	for i := 0; i < numSamples; i++ {
+		StoreF64SampleAsL16Dithered(buffer, value, -value, NoDither)
+		left, right := GetSampleAsI16(buffer)
+		sumNone += float64(left) - float64(right)
+		StoreF64SampleAsL16Dithered(buffer, value, -value, TPDFDither)
+		left, right = GetSampleAsI16(buffer)
+		if left < -1 || left > 1 || right < -1 || right > 1 {
+			t.Fatalf("dithered values out of the expected range: (%d, %d)", left, right)
+		}
+		sumTPDF += float64(left) - float64(right)
+	}
+	if sumNone != 0 { t.Fatalf("expected undithered values to round to zero") }
+	mean := sumTPDF/(2*numSamples)
+	if math.Abs(mean - value) > 0.02 { t.Fatalf("expected dithered mean %f, got %f", value, mean) }
+
+	// clipping still applies
+	StoreF64SampleAsL16Dithered(buffer, 40000, -40000, TPDFDither)
+	left, right := GetSampleAsI16(buffer)
+	if left != 32767 || right != -32768 { t.Fatalf("expected clipped values, got (%d, %d)", left, right) }
+}
+
+func TestStoreF64SampleAsL16Wrap(t *testing.T) {
+	buffer := make([]byte, 4)
+	for _, test := range []struct{ value float64; expected int16 }{
+		{ 32767, 32767 }, { 32768, -32768 }, { -32769, 32767 },
+		{ 70000.7, 4464 }, { -70000.7, -4464 }, { 65536*1e10 + 5, 5 },
+		{ math.NaN(), 0 }, { math.Inf(1), 0 }, { math.Inf(-1), 0 },
+	} {
+		StoreF64SampleAsL16Sat(buffer, test.value, -test.value, ClipWrap)
+		left, _ := GetSampleAsI16(buffer)
+		if left != test.expected { t.Fatalf("expected %f to wrap to %d, got %d", test.value, test.expected, left) }
+	}
+}
@@ -0,0 +1,76 @@
+package edau
+
+import "io"
+import "bytes"
+import "errors"
+
+// Returned by the Seek methods of effects and wrappers when their
+// underlying source doesn't implement [io.Seeker]. See [AudioSource].
+var ErrNotSeekable = errors.New("source is not seekable")
+
+// An AudioSource documents the capabilities that edau expects from the
+// streams passed to its effects and wrappers. Only Read is mandatory, and
+// it must provide L16 little-endian stereo samples. Sources may optionally
+// implement:
+//  - [io.Seeker], required by the Seek methods of the wrappers. See [CanSeek].
+//    Effects and wrappers always have a Seek method, but if their source
+//    can't seek, they return [ErrNotSeekable] instead of panicking.
+//  - Length() int64, the total length in bytes. See [StreamLength].
+//  - [io.Closer], to release the resources associated to the source.
+// [StdAudioStream] is an AudioSource with seeking and length capabilities.
+type AudioSource interface {
+	io.Reader
+}
+
+// Returns whether the given source implements [io.Seeker].
+func CanSeek(source io.Reader) bool {
+	_, canSeek := source.(io.Seeker)
+	return canSeek
+}
+
+// Seeks the given source if it implements [io.Seeker], or returns
+// [ErrNotSeekable] otherwise. Effects and wrappers should use this
+// in their Seek methods instead of asserting io.Seeker directly.
+func seekSource(source io.Reader, offset int64, whence int) (int64, error) {
+	seeker, canSeek := source.(io.Seeker)
+	if !canSeek { return 0, ErrNotSeekable }
+	return seeker.Seek(offset, whence)
+}
+
+// Returns the total length of the given source in bytes, if known. The
+// length is known when the source has a Length() int64 method or when it's
+// a [bytes.Reader] or [io.SectionReader], in which case Size() is used.
+// Otherwise, the second return value will be false.
+func StreamLength(source io.Reader) (int64, bool) {
+	switch typedSource := source.(type) {
+	case *bytes.Reader:
+		return typedSource.Size(), true
+	case *io.SectionReader:
+		return typedSource.Size(), true
+	case interface{ Length() int64 }:
+		return typedSource.Length(), true
+	default:
+		return 0, false
+	}
+}
+
+// Reads from the given source into the buffer, but only whole samples (multiples
+// of 4 bytes). If the source returns an incomplete sample, this function keeps
+// reading until the sample is completed or an error happens, even if the source
+// returns (0, nil) in between, as the bytes already consumed can't be given back
+// without shifting the channels. Incomplete samples found right before an error
+// are discarded.
+func readFrames(source io.Reader, buffer []byte) (int, error) {
+	buffer = buffer[0 : len(buffer) - (len(buffer) & 0b11)]
+	if len(buffer) == 0 { return 0, nil }
+
+	bytesRead := 0
+	for {
+		n, err := source.Read(buffer[bytesRead : ])
+		bytesRead += n
+		if err != nil || (bytesRead > 0 && bytesRead & 0b11 == 0) {
+			return bytesRead - (bytesRead & 0b11), err
+		}
+		if bytesRead == 0 && n == 0 { return 0, nil } // nothing available, don't spin
+	}
+}
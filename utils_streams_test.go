@@ -0,0 +1,38 @@
+package edau
+
+import "io"
+import "bytes"
+import "testing"
+
+// A reader that returns (0, nil) after every read with data.
+type testStallingReader struct {
+	reader io.Reader
+	chunkSize int
+	stall bool
+}
+
+func (self *testStallingReader) Read(buffer []byte) (int, error) {
+	self.stall = !self.stall
+	if !self.stall { return 0, nil }
+	if len(buffer) > self.chunkSize { buffer = buffer[0 : self.chunkSize] }
+	return self.reader.Read(buffer)
+}
+
+func TestReadFramesPartialStall(t *testing.T) {
+	data := make([]byte, 64)
+	for i := range data { data[i] = byte(i) }
+	source := &testStallingReader{ reader: bytes.NewReader(data), chunkSize: 3 }
+
+	var output []byte
+	buffer := make([]byte, 16)
+	for {
+		n, err := readFrames(source, buffer)
+		if n & 0b11 != 0 { t.Fatalf("expected whole frames, got %d bytes", n) }
+		output = append(output, buffer[0 : n]...)
+		if err == io.EOF { break }
+		if err != nil { t.Fatal(err) }
+	}
+	if !bytes.Equal(output, data) {
+		t.Fatalf("expected all data in order, got %v", output)
+	}
+}
@@ -0,0 +1,175 @@
+package edau
+
+import "io"
+import "math"
+import "sync"
+
+const vibratoMaxDepthMs = 20.0
+
+// A Vibrato wraps an audio stream and modulates its pitch with a low
+// frequency oscillator (LFO). The effect is achieved by reading the audio
+// from a short delay line at a varying offset, which speeds up and slows
+// down the playback slightly. The fractional delay reads are interpolated
+// with [InterpHermite6Pt3Ord].
+//
+// The LFO phase advances once per sample and is preserved across reads
+// and seeks, so parameter changes and reads never introduce clicks.
+type Vibrato struct {
+	mutex sync.Mutex
+	source io.Reader
+	sampleRate int
+	depth float64 // in samples
+	lfo *LFO
+	line fractionalDelay
+}
+
+// Creates a new [Vibrato]. The rate must be in [0, sampleRate/2) and the
+// depth (the maximum delay variation, in milliseconds) must be in [0, 20].
+// Typical values are around 5Hz and 1ms - 3ms. This method will panic if
+// any of the values are invalid.
+// If the sample rate is 0, [DefaultSampleRate] is used.
+func NewVibrato(source io.Reader, rateHz, depthMs float64, sampleRate int) *Vibrato {
+	sampleRate = resolveSampleRate(sampleRate, "NewVibrato")
+	assertVibratoDepthValidity(depthMs)
+	maxDepth := msToFrames(vibratoMaxDepthMs, sampleRate)
+	return &Vibrato {
+		source: source,
+		sampleRate: sampleRate,
+		lfo: NewLFO(LFOSine, rateHz, sampleRate),
+		depth: msToFrames(depthMs, sampleRate),
+		line: newFractionalDelay(int(math.Ceil(maxDepth)), InterpHermite6Pt3Ord, 6),
+	}
+}
+
+// Returns the underlying source.
+func (self *Vibrato) Source() io.Reader {
+	return self.source
+}
+
+// Returns the currently configured LFO rate, in Hz.
+func (self *Vibrato) Rate() float64 {
+	return self.lfo.Rate()
+}
+
+// Sets the LFO rate, in Hz. This method will panic if the value
+// is negative or above the Nyquist frequency.
+func (self *Vibrato) SetRate(rateHz float64) {
+	self.lfo.SetRate(rateHz)
+}
+
+// Returns the currently configured depth, in milliseconds.
+func (self *Vibrato) Depth() float64 {
+	self.mutex.Lock()
+	depth := (self.depth*1000.0)/float64(self.sampleRate)
+	self.mutex.Unlock()
+	return depth
+}
+
+// Sets the depth, in milliseconds. The value must be in [0, 20].
+// This method will panic if the value is invalid.
+func (self *Vibrato) SetDepth(depthMs float64) {
+	assertVibratoDepthValidity(depthMs)
+	self.mutex.Lock()
+	self.depth = msToFrames(depthMs, self.sampleRate)
+	self.mutex.Unlock()
+}
+
+// Implements [io.Reader]. The returned read length will always be
+// multiple of 4, aligning to Ebitengine's sample size.
+func (self *Vibrato) Read(buffer []byte) (int, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+
+	n, err := readFrames(self.source, buffer)
+	for i := 0; i < n; i += 4 {
+		left, right := GetSampleAsF64(buffer[i : ])
+		self.line.Push(left, right)
+		delay := self.line.MinDelay() + self.depth*(0.5 + 0.5*self.lfo.Next())
+		left, right = self.line.Tap(delay)
+		StoreNormF64SampleAsL16(buffer[i : ], left, right)
+	}
+	return n, err
+}
+
+// Implements [io.Seeker]. The delay line is cleared after seeking,
+// but the LFO phase is not reset.
+//
+// If the underlying source doesn't implement [io.Seeker], [ErrNotSeekable]
+// is returned.
+func (self *Vibrato) Seek(offset int64, whence int) (int64, error) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	position, err := seekSource(self.source, offset, whence)
+	self.line.Reset()
+	return position, err
+}
+
+func assertVibratoDepthValidity(depthMs float64) {
+	if depthMs < 0 || depthMs > vibratoMaxDepthMs { panic("vibrato depth must be in [0, 20] milliseconds") }
+}
+
+func msToFrames(ms float64, sampleRate int) float64 {
+	return (ms*float64(sampleRate))/1000.0
+}
+
+// --- helper fractionalDelay type for modulated delay effects ---
+
+// A stereo delay line that can be read at fractional delays by
+// interpolating between the stored samples.
+type fractionalDelay struct {
+	left  SlidingWindow
+	right SlidingWindow
+	interpolator InterpolatorFunc
+	windowSize int
+}
+
+// The maxDelay is given in samples. The windowSize is the number of samples
+// required by the interpolator, and it must be multiple of 2.
+func newFractionalDelay(maxDelay int, interpolator InterpolatorFunc, windowSize int) fractionalDelay {
+	if windowSize < 2 || windowSize % 2 != 0 { panic("fractionalDelay windowSize must be multiple of 2") }
+	historySize := maxDelay + windowSize + 1
+	bufferSize  := historySize*8
+	buffer := make([]float64, bufferSize*2)
+	line := fractionalDelay {
+		left:  SlidingWindow{ winSize: historySize, buffer: buffer[ : bufferSize] },
+		right: SlidingWindow{ winSize: historySize, buffer: buffer[bufferSize : ] },
+		interpolator: interpolator,
+		windowSize: windowSize,
+	}
+	line.Reset()
+	return line
+}
+
+// Adds a new sample to the delay line.
+func (self *fractionalDelay) Push(left, right float64) {
+	self.left.Push(left)
+	self.right.Push(right)
+}
+
+// Returns the minimum delay that can be passed to Tap, which depends
+// on the interpolator's window size.
+func (self *fractionalDelay) MinDelay() float64 {
+	return float64(self.windowSize/2)
+}
+
+// Returns the interpolated sample at the given delay, in samples, from the
+// most recently pushed sample. The delay must be between MinDelay() and the
+// maxDelay given on construction (plus MinDelay()).
+func (self *fractionalDelay) Tap(delay float64) (float64, float64) {
+	position := float64(self.left.Size() - 1) - delay
+	start := int(position) - (self.windowSize/2 - 1)
+	x := position - float64(start)
+	end := start + self.windowSize
+	left  := self.interpolator(self.left.Get()[start : end], x)
+	right := self.interpolator(self.right.Get()[start : end], x)
+	return left, right
+}
+
+// Fills the delay line with silence.
+func (self *fractionalDelay) Reset() {
+	self.left.Reset()
+	self.right.Reset()
+	for i := 0; i < self.left.Size(); i++ {
+		self.Push(0, 0)
+	}
+}